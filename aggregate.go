@@ -0,0 +1,366 @@
+package sqlr
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// --------------------------------
+// Has-many aggregation
+// --------------------------------
+
+// A struct with `db:",many"` slice fields is scanned by aggregation: JOIN rows
+// are collapsed into one parent per distinct `db:",pk"` key, and each many
+// field collects the child rows (deduplicated by the child's own pk) in
+// first-seen order. Children can have many fields themselves.
+//
+//	type Order struct {
+//		ID    int     `db:"id,pk"`
+//		Total float64 `db:"total"`
+//	}
+//	type User struct {
+//		ID     int     `db:"id,pk"`
+//		Name   string  `db:"name"`
+//		Orders []Order `db:",many,prefix=o_"`
+//	}
+//
+// Each level maps the columns carrying its (accumulated) prefix, with the
+// prefix stripped, through a regular scanPlan; the root level sees all columns.
+// A child whose key columns are all NULL (LEFT JOIN without a match) is skipped.
+
+// manyTag describes a `db:",many"` field of a struct type.
+type manyTag struct {
+	index   int          // field index in the parent struct
+	prefix  string       // column prefix of the child level
+	sliceT  reflect.Type // []Child or []*Child
+	elemT   reflect.Type // Child (struct)
+	elemPtr bool         // elements are *Child
+}
+
+// manyInfo is the cached result of manyFieldsOf.
+type manyInfo struct {
+	fields []manyTag
+	err    error
+}
+
+// manyCache maps struct types to their many fields. The set of types seen by
+// a program is bounded, so entries are never evicted.
+var manyCache sync.Map // reflect.Type → manyInfo
+
+// manyFieldsOf returns the `db:",many"` fields declared directly on struct type t.
+func manyFieldsOf(t reflect.Type) ([]manyTag, error) {
+	if v, ok := manyCache.Load(t); ok {
+		mi := v.(manyInfo)
+		return mi.fields, mi.err
+	}
+	var mi manyInfo
+	for i := 0; i < t.NumField() && mi.err == nil; i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if f.PkgPath != "" || tag == "" || tag == "-" {
+			continue
+		}
+		mt := manyTag{index: i, sliceT: f.Type}
+		many := false
+		for _, p := range strings.Split(tag, ",")[1:] {
+			p = strings.TrimSpace(p)
+			switch {
+			case p == "many":
+				many = true
+			case strings.HasPrefix(p, "prefix="):
+				mt.prefix = p[len("prefix="):]
+			}
+		}
+		if !many {
+			continue
+		}
+		if f.Type.Kind() == reflect.Slice {
+			mt.elemT = f.Type.Elem()
+			if mt.elemT.Kind() == reflect.Pointer {
+				mt.elemT, mt.elemPtr = mt.elemT.Elem(), true
+			}
+		}
+		if mt.elemT == nil || mt.elemT.Kind() != reflect.Struct {
+			mi.err = fmt.Errorf("sqlr: %s.%s: `db:\",many\"` requires a slice of structs, got %s", t, f.Name, f.Type)
+			break
+		}
+		mi.fields = append(mi.fields, mt)
+	}
+	manyCache.Store(t, mi)
+	return mi.fields, mi.err
+}
+
+// hasMany reports whether t (or the struct it points to) declares many fields.
+func hasMany(t reflect.Type) bool {
+	t = canonicalStructType(t)
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	fields, err := manyFieldsOf(t)
+	return len(fields) > 0 || err != nil
+}
+
+// aggLevel is the scan plan of one level of the has-many tree.
+type aggLevel struct {
+	t        reflect.Type // struct type of this level
+	optional bool         // child level: skip rows whose key is all NULL
+	plan     *scanPlan
+	st       *scanState
+	pkCols   []int // column indexes of the level's pk fields
+	many     []manyTag
+	children []*aggLevel // parallel to many
+}
+
+// aggNode is one aggregated value: a parent and the children collected so far.
+type aggNode struct {
+	v    reflect.Value // *T
+	sets []aggSet      // one per many field
+}
+
+// aggSet collects distinct nodes in first-seen order.
+type aggSet struct {
+	index map[any]*aggNode
+	order []*aggNode
+}
+
+// aggregator collapses the rows of a result set into a tree of aggNodes.
+type aggregator struct {
+	rows    *sql.Rows
+	raw     []any // raw column values of the current row
+	targets []any
+	root    *aggLevel
+	roots   aggSet
+}
+
+// newAggregator prepares the levels of structT for the result columns.
+func newAggregator(rows *sql.Rows, structT reflect.Type, fm *fieldMapper) (*aggregator, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	a := &aggregator{
+		rows:    rows,
+		raw:     make([]any, len(cols)),
+		targets: make([]any, len(cols)),
+	}
+	for i := range a.raw {
+		a.targets[i] = &a.raw[i]
+	}
+	mapped := make([]bool, len(cols))
+	path := map[reflect.Type]bool{}
+	if a.root, err = newAggLevel(fm, structT, cols, "", false, mapped, path); err != nil {
+		return nil, err
+	}
+	if fm.strictCols {
+		var unmapped []string
+		for i, ok := range mapped {
+			if !ok {
+				unmapped = append(unmapped, cols[i])
+			}
+		}
+		if len(unmapped) > 0 {
+			return nil, fmt.Errorf("%w: %s (into %s)", ErrColumnUnmapped, quoteList(unmapped), structT)
+		}
+	}
+	return a, nil
+}
+
+// newAggLevel builds the level for struct type t, seeing the columns that
+// start with prefix, and recursively its children. mapped records which
+// columns are used by some level.
+func newAggLevel(fm *fieldMapper, t reflect.Type, cols []string, prefix string, optional bool, mapped []bool, path map[reflect.Type]bool) (*aggLevel, error) {
+	if path[t] {
+		return nil, fmt.Errorf("sqlr: recursive `db:\",many\"` field of type %s", t)
+	}
+	path[t] = true
+	defer delete(path, t)
+
+	// Columns outside this level's prefix are renamed to a name no field can
+	// have, so the regular plan sinks them.
+	lcols := make([]string, len(cols))
+	for i, c := range cols {
+		if len(c) >= len(prefix) && (c[:len(prefix)] == prefix || fm.fold && strings.EqualFold(c[:len(prefix)], prefix)) {
+			lcols[i] = c[len(prefix):]
+		} else {
+			lcols[i] = "\x00"
+		}
+	}
+	plan, err := fm.cachedPlan(lcols, t)
+	if err != nil {
+		return nil, err
+	}
+	if plan.unfilledErr != nil {
+		return nil, plan.unfilledErr
+	}
+	lv := &aggLevel{t: t, optional: optional, plan: plan, st: plan.newState()}
+
+	fmap := fm.fieldIndexMap(t)
+	for i, k := range plan.kinds {
+		if k == ckSink {
+			continue
+		}
+		mapped[i] = true
+		if fmap[fm.key(lcols[i])].pk {
+			lv.pkCols = append(lv.pkCols, i)
+		}
+	}
+	if len(lv.pkCols) == 0 {
+		return nil, fmt.Errorf("sqlr: has-many scan into %s: no column maps to a `db:\",pk\"` field", t)
+	}
+
+	if lv.many, err = manyFieldsOf(t); err != nil {
+		return nil, err
+	}
+	for _, mt := range lv.many {
+		child, err := newAggLevel(fm, mt.elemT, cols, prefix+mt.prefix, true, mapped, path)
+		if err != nil {
+			return nil, err
+		}
+		lv.children = append(lv.children, child)
+	}
+	return lv, nil
+}
+
+// next consumes the current row.
+func (a *aggregator) next() error {
+	if err := a.rows.Scan(a.targets...); err != nil {
+		return err
+	}
+	return a.root.add(&a.roots, a)
+}
+
+// add merges the current row into set at this level and below.
+func (lv *aggLevel) add(set *aggSet, a *aggregator) error {
+	key, ok := lv.key(a.raw)
+	if !ok {
+		return nil
+	}
+	n := set.index[key]
+	if n == nil {
+		n = &aggNode{v: reflect.New(lv.t), sets: make([]aggSet, len(lv.many))}
+		// database/sql allows scanning the current row again, so the
+		// level's regular plan fills the new value.
+		if err := lv.st.scanRow(a.rows, lv.plan, n.v.Elem()); err != nil {
+			return err
+		}
+		if set.index == nil {
+			set.index = make(map[any]*aggNode)
+		}
+		set.index[key] = n
+		set.order = append(set.order, n)
+	}
+	for i, child := range lv.children {
+		if err := child.add(&n.sets[i], a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// key returns the comparable key of the current row at this level.
+// It reports false for an optional level whose key columns are all NULL.
+func (lv *aggLevel) key(raw []any) (any, bool) {
+	if len(lv.pkCols) == 1 {
+		v := raw[lv.pkCols[0]]
+		if v == nil && lv.optional {
+			return nil, false
+		}
+		if b, ok := v.([]byte); ok {
+			return string(b), true
+		}
+		return v, true
+	}
+	var sb strings.Builder
+	null := true
+	for _, i := range lv.pkCols {
+		v := raw[i]
+		if v != nil {
+			null = false
+		}
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		fmt.Fprintf(&sb, "%T:%v\x00", v, v)
+	}
+	if null && lv.optional {
+		return nil, false
+	}
+	return sb.String(), true
+}
+
+// finish assigns the collected children of n to its many fields, recursively.
+func (lv *aggLevel) finish(n *aggNode) {
+	for i, mt := range lv.many {
+		set := n.sets[i]
+		if len(set.order) == 0 {
+			continue
+		}
+		sv := reflect.MakeSlice(mt.sliceT, len(set.order), len(set.order))
+		for j, c := range set.order {
+			lv.children[i].finish(c)
+			if mt.elemPtr {
+				sv.Index(j).Set(c.v)
+			} else {
+				sv.Index(j).Set(c.v.Elem())
+			}
+		}
+		n.v.Elem().Field(mt.index).Set(sv)
+	}
+}
+
+// scanAggregate aggregates all rows into the slice rv ([]T or []*T).
+func scanAggregate(rows *sql.Rows, rv reflect.Value, fm *fieldMapper) error {
+	elemT := rv.Type().Elem()
+	a, err := newAggregator(rows, canonicalStructType(elemT), fm)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		if err := a.next(); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, n := range a.roots.order {
+		a.root.finish(n)
+		if elemT.Kind() == reflect.Pointer {
+			rv.Set(reflect.Append(rv, n.v))
+		} else {
+			rv.Set(reflect.Append(rv, n.v.Elem()))
+		}
+	}
+	return nil
+}
+
+// scanAggregateOne aggregates all rows into the struct pointed to by dest.
+// It returns sql.ErrNoRows without rows and ErrMoreThanOneRow when the rows
+// collapse into more than one parent.
+func scanAggregateOne(rows *sql.Rows, dest reflect.Value, fm *fieldMapper) error {
+	a, err := newAggregator(rows, dest.Type(), fm)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		if err := a.next(); err != nil {
+			return err
+		}
+		if len(a.roots.order) > 1 {
+			return ErrMoreThanOneRow
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(a.roots.order) == 0 {
+		return sql.ErrNoRows
+	}
+	n := a.roots.order[0]
+	a.root.finish(n)
+	dest.Set(n.v.Elem())
+	return nil
+}
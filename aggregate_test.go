@@ -0,0 +1,141 @@
+package sqlr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type aggItem struct {
+	SKU string `db:"sku,pk"`
+	Qty int    `db:"qty"`
+}
+
+type aggOrder struct {
+	ID    int       `db:"id,pk"`
+	Total float64   `db:"total"`
+	Items []aggItem `db:",many,prefix=i_"`
+}
+
+type aggUser struct {
+	ID     int         `db:"id,pk"`
+	Name   string      `db:"name"`
+	Orders []*aggOrder `db:",many,prefix=o_"`
+}
+
+var aggCols = []string{"id", "name", "o_id", "o_total", "o_i_sku", "o_i_qty"}
+
+// TestAggregate_ScanAll_NestedLevels verifies that JOIN rows collapse into parents by pk,
+// children are deduplicated per parent in first-seen order across nested levels, and
+// LEFT JOIN rows without a child (NULL key) leave the collection empty.
+func TestAggregate_ScanAll_NestedLevels(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows(aggCols).
+		AddRow(1, "alice", 10, 5.0, "a", 1).
+		AddRow(2, "bob", nil, nil, nil, nil).
+		AddRow(1, "alice", 10, 5.0, "b", 2).
+		AddRow(1, "alice", 11, 7.5, "a", 3).
+		AddRow(1, "alice", 10, 5.0, "a", 1)) // duplicate item row
+
+	var out []aggUser
+	assertNoError(t, New(Postgres).Write("SELECT ...").ScanAll(db, &out))
+
+	if len(out) != 2 || out[0].ID != 1 || out[1].Name != "bob" {
+		t.Fatalf("parents: %+v", out)
+	}
+	if out[1].Orders != nil {
+		t.Fatalf("bob must have no orders, got %+v", out[1].Orders)
+	}
+	o := out[0].Orders
+	if len(o) != 2 || o[0].ID != 10 || o[1].ID != 11 || o[1].Total != 7.5 {
+		t.Fatalf("orders: %+v %+v", o[0], o[1])
+	}
+	if len(o[0].Items) != 2 || o[0].Items[0].SKU != "a" || o[0].Items[1].Qty != 2 {
+		t.Fatalf("items of order 10: %+v", o[0].Items)
+	}
+	if len(o[1].Items) != 1 || o[1].Items[0].Qty != 3 {
+		t.Fatalf("items of order 11: %+v", o[1].Items)
+	}
+}
+
+// TestAggregate_ScanOne_And_All verifies aggregation through ScanOne (exactly one parent)
+// and the All helper with pointer elements.
+func TestAggregate_ScanOne_And_All(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	s := New(MySQL)
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows(aggCols).
+		AddRow(1, "alice", 10, 5.0, "a", 1).
+		AddRow(1, "alice", 11, 6.0, "b", 2))
+	var u aggUser
+	assertNoError(t, s.Write("SELECT ...").ScanOne(db, &u))
+	if u.Name != "alice" || len(u.Orders) != 2 {
+		t.Fatalf("got %+v", u)
+	}
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows(aggCols).
+		AddRow(1, "alice", 10, 5.0, "a", 1).
+		AddRow(2, "bob", 12, 1.0, "c", 1))
+	if err := s.Write("SELECT ...").ScanOne(db, &u); !errors.Is(err, ErrMoreThanOneRow) {
+		t.Fatalf("expected ErrMoreThanOneRow, got %v", err)
+	}
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows(aggCols))
+	if err := s.Write("SELECT ...").ScanOne(db, &u); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows(aggCols).
+		AddRow(1, "alice", 10, 5.0, "a", 1).
+		AddRow(1, "alice", 10, 5.0, "b", 1))
+	users, err := All[*aggUser](context.Background(), db, s.Write("SELECT ..."))
+	assertNoError(t, err)
+	if len(users) != 1 || len(users[0].Orders[0].Items) != 2 {
+		t.Fatalf("got %+v", users)
+	}
+}
+
+// TestAggregate_Errors covers missing pk columns, invalid many fields, strict columns and
+// the streaming iterator, which does not aggregate.
+func TestAggregate_Errors(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	s := New(Postgres)
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"name", "o_id"}).AddRow("a", 1))
+	var out []aggUser
+	if err := s.Write("SELECT ...").ScanAll(db, &out); err == nil || !strings.Contains(err.Error(), "pk") {
+		t.Fatalf("expected missing pk error, got %v", err)
+	}
+
+	type Bad struct {
+		ID   int   `db:"id,pk"`
+		Tags []int `db:",many"`
+	}
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var bad []Bad
+	if err := s.Write("SELECT ...").ScanAll(db, &bad); err == nil || !strings.Contains(err.Error(), "slice of structs") {
+		t.Fatalf("expected invalid many field error, got %v", err)
+	}
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows(append(aggCols, "extra")).
+		AddRow(1, "alice", 10, 5.0, "a", 1, "x"))
+	err := New(Postgres, Config{StrictColumns: true}).Write("SELECT ...").ScanAll(db, &out)
+	if !errors.Is(err, ErrColumnUnmapped) || !strings.Contains(err.Error(), `"extra"`) {
+		t.Fatalf("expected ErrColumnUnmapped naming only extra, got %v", err)
+	}
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows(aggCols).AddRow(1, "alice", 10, 5.0, "a", 1))
+	for _, err := range Rows[aggUser](context.Background(), db, s.Write("SELECT ...")) {
+		if err == nil || !strings.Contains(err.Error(), "many") {
+			t.Fatalf("expected Rows to reject has-many types, got %v", err)
+		}
+	}
+}
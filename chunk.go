@@ -0,0 +1,143 @@
+package sqlr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TxBeginner abstracts *sql.DB BeginTx for transactional helpers.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// ExecChunked builds and executes a statement containing exactly one
+// :name{...} rows-block, splitting the bound rows into as many statements as
+// needed to keep each one within Config.MaxParams. Statements run in order
+// and the summed RowsAffected is returned; chunks whose driver does not report
+// it are left out of the sum. Like Exec, it releases the builder.
+//
+// The other placeholders of the statement are repeated in every chunk. If
+// they alone leave no room for a single row, ErrTooManyParams is returned.
+// Chunks are not atomic: pass a *sql.Tx, or use ExecChunkedTx.
+func (b *Builder) ExecChunked(ctx context.Context, db Execer) (int64, error) {
+	if b.released {
+		return 0, ErrBuilderReleased
+	}
+	defer b.Release()
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.tx != nil {
+		db = b.tx
+	}
+
+	in := b.inputs
+	if len(b.bag) > 0 {
+		in = append(in, b.bag)
+	}
+	var (
+		src    string
+		tokens []token
+		params int
+		err    error
+	)
+	if b.stmt != nil {
+		src, tokens, params = b.stmt.sql, b.stmt.tokens, b.stmt.params
+	} else {
+		src = strings.Join(b.parts, "")
+		if tokens, params, err = tokenize(b.s, src, false); err != nil {
+			return 0, err
+		}
+	}
+
+	// Locate the single rows-block and resolve its rows once.
+	var block token
+	blocks := 0
+	for _, tok := range tokens {
+		if tok.kind == tkRows {
+			block = tok
+			blocks++
+		}
+	}
+	if blocks != 1 {
+		return 0, fmt.Errorf("sqlr: ExecChunked requires exactly one :name{...} block, got %d", blocks)
+	}
+	var e emitter
+	e.init(b.s, in, 0, 0)
+	rows, ok := e.rowsLookup(block.name)
+	if !ok {
+		return 0, newBuildError(src, block.pos, fmt.Errorf("%w: :%s{...}", ErrParamMissing, block.name))
+	}
+	if len(rows) == 0 {
+		return 0, newBuildError(src, block.pos, fmt.Errorf("%w: :%s{...}", ErrRowsEmpty, block.name))
+	}
+
+	// Rows per chunk: what is left of the limit once the other placeholders
+	// (measured on a single-row render) are accounted for.
+	per := len(rows)
+	if limit := b.s.config.MaxParams; limit > 0 {
+		_, args, err := renderTokens(b.s, src, tokens, params, in, rows[:1])
+		if err != nil {
+			return 0, err
+		}
+		fixed := len(args) - len(block.cols)
+		per = (limit - fixed) / len(block.cols)
+		if per < 1 {
+			return 0, newBuildError(src, block.pos, fmt.Errorf("%w: one row of :%s{...} needs %d, limit=%d", ErrTooManyParams, block.name, len(args), limit))
+		}
+	}
+
+	var total int64
+	for lo := 0; lo < len(rows); lo += per {
+		hi := min(lo+per, len(rows))
+		q, args, err := renderTokens(b.s, src, tokens, params, in, rows[lo:hi])
+		if err != nil {
+			return total, err
+		}
+		info := QueryInfo{Op: OpExec, SQL: q, Args: args, Dialect: b.s.dialect, Rows: -1}
+		hctx, start := b.s.hookBefore(ctx, &info)
+		res, err := db.ExecContext(hctx, q, args...)
+		if err == nil {
+			if n, rerr := res.RowsAffected(); rerr == nil {
+				info.Rows = n
+				total += n
+			}
+		}
+		b.s.hookAfter(hctx, &info, start, err)
+		if err != nil {
+			return total, fmt.Errorf("sqlr: chunk rows %d-%d: %w", lo, hi-1, err)
+		}
+	}
+	return total, nil
+}
+
+// ExecChunkedTx runs ExecChunked inside a transaction started on db with
+// opts: all chunks are committed together, or rolled back on the first error.
+// A builder created from a Tx runs in that transaction instead.
+func (b *Builder) ExecChunkedTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions) (int64, error) {
+	if b.released {
+		return 0, ErrBuilderReleased
+	}
+	if b.tx != nil {
+		return b.ExecChunked(ctx, b.tx)
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		b.Release()
+		return 0, err
+	}
+	n, err := b.ExecChunked(ctx, tx)
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil && !errors.Is(rerr, sql.ErrTxDone) {
+			return 0, errors.Join(err, fmt.Errorf("sqlr: rollback: %w", rerr))
+		}
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("sqlr: commit: %w", err)
+	}
+	return n, nil
+}
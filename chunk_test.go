@@ -0,0 +1,165 @@
+package sqlr
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type chunkRow struct {
+	A int    `db:"a"`
+	B string `db:"b"`
+}
+
+func chunkRows(n int) []chunkRow {
+	rows := make([]chunkRow, n)
+	for i := range rows {
+		rows[i] = chunkRow{A: i, B: string(rune('a' + i))}
+	}
+	return rows
+}
+
+// TestExecChunked_SplitsWithinLimit verifies that rows are split into statements that each
+// stay within MaxParams (accounting for the other placeholders), run in order, and that
+// RowsAffected is summed.
+func TestExecChunked_SplitsWithinLimit(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	// limit 7, one fixed param + 2 per row → 3 rows per chunk: 3 + 3 + 1
+	s := New(Postgres, Config{MaxParams: 7})
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO t(a,b,tag) SELECT *, $1 FROM (VALUES ($2, $3), ($4, $5), ($6, $7)) v")).
+		WithArgs("tag", 0, "a", 1, "b", 2, "c").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO t(a,b,tag) SELECT *, $1 FROM (VALUES ($2, $3), ($4, $5), ($6, $7)) v")).
+		WithArgs("tag", 3, "d", 4, "e", 5, "f").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO t(a,b,tag) SELECT *, $1 FROM (VALUES ($2, $3)) v")).
+		WithArgs("tag", 6, "g").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := s.Write("INSERT INTO t(a,b,tag) SELECT *, :tag FROM (VALUES :rows{a,b}) v").
+		Bind("rows", chunkRows(7), "tag", "tag").
+		ExecChunked(context.Background(), db)
+	assertNoError(t, err)
+	if n != 7 {
+		t.Fatalf("RowsAffected=%d, want 7", n)
+	}
+	assertNoError(t, mock.ExpectationsWereMet())
+}
+
+// TestExecChunked_CompiledAndUnlimited verifies chunking from a compiled statement and a
+// single statement when MaxParams is unlimited.
+func TestExecChunked_CompiledAndUnlimited(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	st, err := New(SQLite, Config{MaxParams: 4}).Compile("INSERT INTO t VALUES :rows{a,b}")
+	assertNoError(t, err)
+	for i := 0; i < 3; i++ {
+		mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 2))
+	}
+	n, err := st.Bind("rows", chunkRows(6)).ExecChunked(context.Background(), db)
+	assertNoError(t, err)
+	if n != 6 {
+		t.Fatalf("RowsAffected=%d, want 6", n)
+	}
+
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 50))
+	n, err = New(SQLite, Config{MaxParams: -1}).Write("INSERT INTO t VALUES :rows{a,b}").
+		Bind("rows", chunkRows(50)).ExecChunked(context.Background(), db)
+	assertNoError(t, err)
+	if n != 50 {
+		t.Fatalf("RowsAffected=%d, want 50", n)
+	}
+	assertNoError(t, mock.ExpectationsWereMet())
+}
+
+// TestExecChunked_Errors covers the block count, rows without room, and a failing chunk.
+func TestExecChunked_Errors(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := New(MySQL).Write("INSERT INTO t VALUES (:a)").Bind("a", 1).ExecChunked(ctx, db)
+	if err == nil || !strings.Contains(err.Error(), "exactly one") {
+		t.Fatalf("expected block count error, got %v", err)
+	}
+
+	_, err = New(MySQL, Config{MaxParams: 3}).Write("INSERT INTO t SELECT :x, :y, v.* FROM (VALUES :rows{a,b}) v").
+		Bind("rows", chunkRows(2), "x", 1, "y", 2).ExecChunked(ctx, db)
+	if !errors.Is(err, ErrTooManyParams) {
+		t.Fatalf("expected ErrTooManyParams, got %v", err)
+	}
+
+	_, err = New(MySQL).Write("INSERT INTO t VALUES :rows{a,b}").Bind("rows", []chunkRow{}).ExecChunked(ctx, db)
+	if !errors.Is(err, ErrRowsEmpty) {
+		t.Fatalf("expected ErrRowsEmpty, got %v", err)
+	}
+
+	boom := errors.New("boom")
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT").WillReturnError(boom)
+	n, err := New(MySQL, Config{MaxParams: 4}).Write("INSERT INTO t VALUES :rows{a,b}").
+		Bind("rows", chunkRows(6)).ExecChunked(ctx, db)
+	if !errors.Is(err, boom) || n != 2 {
+		t.Fatalf("expected boom after 2 rows, got n=%d err=%v", n, err)
+	}
+
+	// A chunk whose driver reports no row count is left out of the sum.
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewErrorResult(errors.New("no count")))
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 2))
+	n, err = New(MySQL, Config{MaxParams: 4}).Write("INSERT INTO t VALUES :rows{a,b}").
+		Bind("rows", chunkRows(4)).ExecChunked(ctx, db)
+	assertNoError(t, err)
+	if n != 2 {
+		t.Fatalf("RowsAffected=%d, want 2", n)
+	}
+	assertNoError(t, mock.ExpectationsWereMet())
+}
+
+// TestExecChunkedTx_CommitAndRollback verifies the transactional variant.
+func TestExecChunkedTx_CommitAndRollback(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	s := New(SQLServer, Config{MaxParams: 4})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	n, err := s.Write("INSERT INTO t VALUES :rows{a,b}").Bind("rows", chunkRows(3)).ExecChunkedTx(ctx, db, nil)
+	assertNoError(t, err)
+	if n != 3 {
+		t.Fatalf("RowsAffected=%d, want 3", n)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT").WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+	if _, err := s.Write("INSERT INTO t VALUES :rows{a,b}").Bind("rows", chunkRows(3)).ExecChunkedTx(ctx, db, nil); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	// Commit and rollback failures are told apart from the chunk errors.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(errors.New("conn lost"))
+	_, err = s.Write("INSERT INTO t VALUES :rows{a,b}").Bind("rows", chunkRows(1)).ExecChunkedTx(ctx, db, nil)
+	if err == nil || err.Error() != "sqlr: commit: conn lost" {
+		t.Fatalf("expected commit error, got %v", err)
+	}
+
+	boom := errors.New("boom")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT").WillReturnError(boom)
+	mock.ExpectRollback().WillReturnError(errors.New("conn lost"))
+	_, err = s.Write("INSERT INTO t VALUES :rows{a,b}").Bind("rows", chunkRows(1)).ExecChunkedTx(ctx, db, nil)
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "sqlr: rollback: conn lost") {
+		t.Fatalf("expected chunk and rollback errors, got %v", err)
+	}
+	assertNoError(t, mock.ExpectationsWereMet())
+}
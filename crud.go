@@ -0,0 +1,445 @@
+package sqlr
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// --------------------------------
+// CRUD helpers
+// --------------------------------
+
+// Insert, Update and Delete generate single-table statements from the `db`
+// tags of a struct and return a regular *Builder with the struct bound, so
+// more SQL (RETURNING, extra WHERE conditions) can be appended and more
+// values bound before executing it. Besides the mapping rules of Bind, they
+// honor these tag options:
+//
+//	ID        int       `db:"id,pk,omitempty"`    // key of Update/Delete
+//	CreatedAt time.Time `db:"created_at,readonly"` // never written
+//	Note      *string   `db:"note,omitempty"`     // skipped when zero
+//
+// Table and column names are always quoted for the dialect, each dotted
+// part on its own ("public.users" → "public"."users"), so reserved words
+// and mixed-case names are kept as written; an empty part ("", "a..b")
+// fails with ErrIdentInvalid.
+
+// crudField is a struct column written by the CRUD helpers.
+type crudField struct {
+	key   string // fieldIndexMap key, also used as the :param name
+	ident string // col quoted for the dialect
+	fieldInfo
+}
+
+// Insert returns a Builder for INSERT INTO table (...) VALUES (...) with the
+// columns of v, a struct or a pointer to one. readonly fields are left out,
+// and so are omitempty fields holding a zero value.
+//
+// v can also be a slice of structs: the rows are inserted with a
+// :rows{...} block (see ExecChunked for large slices). omitempty is not
+// applied then, since every row must have the same columns.
+func (s *SQLR) Insert(table string, v any) *Builder {
+	rv := reflect.ValueOf(v)
+	multi := rv.Kind() == reflect.Slice
+	var t reflect.Type
+	if multi {
+		t = canonicalStructType(rv.Type().Elem())
+	} else {
+		var err error
+		if rv, t, err = crudStruct("Insert", v); err != nil {
+			return s.failed(err)
+		}
+	}
+	if t.Kind() != reflect.Struct {
+		return s.failed(fmt.Errorf("sqlr: Insert expects a struct or a slice of structs, got %T", v))
+	}
+	fields, err := s.crudFields(t)
+	if err != nil {
+		return s.failed(err)
+	}
+
+	var cols []crudField
+	for _, f := range fields {
+		if f.readonly || !multi && f.omitempty && crudZero(rv, f) {
+			continue
+		}
+		cols = append(cols, f)
+	}
+	if len(cols) == 0 {
+		return s.failed(fmt.Errorf("sqlr: Insert into %s: no column to insert", table))
+	}
+	tbl, err := s.crudIdent(table)
+	if err != nil {
+		return s.failed(err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(tbl)
+	sb.WriteString(" (")
+	for i, f := range cols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(f.ident)
+	}
+	if multi {
+		sb.WriteString(") VALUES :rows{")
+		for i, f := range cols {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(f.key)
+		}
+		sb.WriteByte('}')
+		return s.Write(sb.String()).Bind("rows", v)
+	}
+	sb.WriteString(") VALUES (")
+	for i, f := range cols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte(':')
+		sb.WriteString(f.key)
+	}
+	sb.WriteByte(')')
+	return s.Write(sb.String()).Bind(v).Bind(crudArgs(rv, cols))
+}
+
+// Update returns a Builder for UPDATE table SET ... WHERE ... from the
+// struct v. The WHERE clause matches the key columns, or the `db:",pk"`
+// fields when no keys are given. Every other field is set, except pk,
+// readonly and zero omitempty fields.
+func (s *SQLR) Update(table string, v any, keys ...string) *Builder {
+	rv, t, err := crudStruct("Update", v)
+	if err != nil {
+		return s.failed(err)
+	}
+	fields, err := s.crudFields(t)
+	if err != nil {
+		return s.failed(err)
+	}
+	where, err := s.crudKeys(t, fields, keys)
+	if err != nil {
+		return s.failed(err)
+	}
+
+	tbl, err := s.crudIdent(table)
+	if err != nil {
+		return s.failed(err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("UPDATE ")
+	sb.WriteString(tbl)
+	sb.WriteString(" SET ")
+	var set []crudField
+	for _, f := range fields {
+		if f.pk || f.readonly || f.omitempty && crudZero(rv, f) ||
+			slices.ContainsFunc(where, func(k crudField) bool { return k.key == f.key }) {
+			continue
+		}
+		if len(set) > 0 {
+			sb.WriteString(", ")
+		}
+		s.crudAssign(&sb, f)
+		set = append(set, f)
+	}
+	if len(set) == 0 {
+		return s.failed(fmt.Errorf("sqlr: Update of %s: no column to set", table))
+	}
+	s.crudWhere(&sb, where)
+	return s.Write(sb.String()).Bind(v).Bind(crudArgs(rv, set, where))
+}
+
+// Delete returns a Builder for DELETE FROM table WHERE ... matching the key
+// columns of the struct v, or its `db:",pk"` fields when no keys are given.
+func (s *SQLR) Delete(table string, v any, keys ...string) *Builder {
+	rv, t, err := crudStruct("Delete", v)
+	if err != nil {
+		return s.failed(err)
+	}
+	fields, err := s.crudFields(t)
+	if err != nil {
+		return s.failed(err)
+	}
+	where, err := s.crudKeys(t, fields, keys)
+	if err != nil {
+		return s.failed(err)
+	}
+
+	tbl, err := s.crudIdent(table)
+	if err != nil {
+		return s.failed(err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("DELETE FROM ")
+	sb.WriteString(tbl)
+	s.crudWhere(&sb, where)
+	return s.Write(sb.String()).Bind(v).Bind(crudArgs(rv, where))
+}
+
+// Upsert returns a Builder inserting rows (a struct, or a slice of structs
+// bound through a :rows{...} block) into table and updating the existing
+// rows that conflict on the conflict columns, in the form of the dialect:
+//
+//	Postgres, SQLite: INSERT ... ON CONFLICT (conflict) DO UPDATE SET c = EXCLUDED.c
+//	MySQL:            INSERT ... ON DUPLICATE KEY UPDATE c = VALUES(c)
+//	SQL Server:       MERGE INTO ... USING (VALUES ...) AS source (...) ON ...;
+//	Oracle:           MERGE INTO ... USING (VALUES ...) source (...) ON (...)
+//
+// The inserted columns are those of Insert for a slice; conflict defaults to
+// the pk fields, and a nil update to every inserted column that is neither a
+// conflict column nor a pk. An empty, non-nil update only inserts the rows
+// that do not conflict. MySQL matches any unique key and ignores conflict
+// except for that no-op case; Oracle needs table value constructors (23ai).
+// Custom dialects pick one of these forms with Syntax.Upsert.
+func (s *SQLR) Upsert(table string, rows any, conflict, update []string) *Builder {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice {
+		if _, _, err := crudStruct("Upsert", rows); err != nil {
+			return s.failed(err)
+		}
+		one := reflect.MakeSlice(reflect.SliceOf(rv.Type()), 1, 1)
+		one.Index(0).Set(rv)
+		rv = one
+	}
+	t := canonicalStructType(rv.Type().Elem())
+	if t.Kind() != reflect.Struct {
+		return s.failed(fmt.Errorf("sqlr: Upsert expects a struct or a slice of structs, got %T", rows))
+	}
+	fields, err := s.crudFields(t)
+	if err != nil {
+		return s.failed(err)
+	}
+	cols := slices.DeleteFunc(fields, func(f crudField) bool { return f.readonly })
+	on, err := s.crudKeys(t, cols, conflict)
+	if err != nil {
+		return s.failed(err)
+	}
+	var set []crudField
+	if update == nil {
+		for _, f := range cols {
+			if !f.pk && !slices.ContainsFunc(on, func(k crudField) bool { return k.key == f.key }) {
+				set = append(set, f)
+			}
+		}
+	} else if len(update) > 0 {
+		if set, err = s.crudKeys(t, cols, update); err != nil {
+			return s.failed(err)
+		}
+	}
+
+	tbl, err := s.crudIdent(table)
+	if err != nil {
+		return s.failed(err)
+	}
+
+	// list writes the columns of fs, each formatted by col.
+	var sb strings.Builder
+	list := func(fs []crudField, sep string, col func(c string)) {
+		for i, f := range fs {
+			if i > 0 {
+				sb.WriteString(sep)
+			}
+			col(f.ident)
+		}
+	}
+	plain := func(c string) { sb.WriteString(c) }
+	block := func() {
+		sb.WriteString(":rows{")
+		for i, f := range cols {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(f.key)
+		}
+		sb.WriteByte('}')
+	}
+	insert := func() {
+		sb.WriteString("INSERT INTO ")
+		sb.WriteString(tbl)
+		sb.WriteString(" (")
+		list(cols, ", ", plain)
+		sb.WriteString(") VALUES ")
+		block()
+	}
+	merge := func(as, onOpen, onClose, setPrefix, end string) {
+		sb.WriteString("MERGE INTO ")
+		sb.WriteString(tbl)
+		sb.WriteString(as + "target USING (VALUES ")
+		block()
+		sb.WriteString(")" + as + "source (")
+		list(cols, ", ", plain)
+		sb.WriteString(") ON " + onOpen)
+		list(on, " AND ", func(c string) { sb.WriteString("target." + c + " = source." + c) })
+		sb.WriteString(onClose)
+		if len(set) > 0 {
+			sb.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+			list(set, ", ", func(c string) { sb.WriteString(setPrefix + c + " = source." + c) })
+		}
+		sb.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+		list(cols, ", ", plain)
+		sb.WriteString(") VALUES (")
+		list(cols, ", ", func(c string) { sb.WriteString("source." + c) })
+		sb.WriteString(")" + end)
+	}
+
+	switch s.syntax.Upsert {
+	case UpsertOnConflict:
+		insert()
+		sb.WriteString(" ON CONFLICT (")
+		list(on, ", ", plain)
+		if len(set) == 0 {
+			sb.WriteString(") DO NOTHING")
+			break
+		}
+		sb.WriteString(") DO UPDATE SET ")
+		list(set, ", ", func(c string) { sb.WriteString(c + " = EXCLUDED." + c) })
+	case UpsertOnDuplicateKey:
+		insert()
+		sb.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if len(set) == 0 {
+			list(on[:1], "", func(c string) { sb.WriteString(c + " = " + c) })
+			break
+		}
+		list(set, ", ", func(c string) { sb.WriteString(c + " = VALUES(" + c + ")") })
+	case UpsertMergeAs:
+		merge(" AS ", "", "", "", ";")
+	case UpsertMerge:
+		merge(" ", "(", ")", "target.", "")
+	default:
+		return s.failed(fmt.Errorf("sqlr: Upsert is not supported for dialect %s", s.dialect))
+	}
+	return s.Write(sb.String()).Bind("rows", rv.Interface())
+}
+
+// failed returns a Builder that reports err when built or executed.
+func (s *SQLR) failed(err error) *Builder {
+	b := s.Write("")
+	b.err = err
+	return b
+}
+
+// crudStruct dereferences v down to a struct value.
+func crudStruct(op string, v any) (reflect.Value, reflect.Type, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("sqlr: %s expects a struct or a non-nil pointer to one, got %T", op, v)
+	}
+	return rv, rv.Type(), nil
+}
+
+// crudFields returns the columns of struct type t in declaration order.
+func (s *SQLR) crudFields(t reflect.Type) ([]crudField, error) {
+	m := s.mapper.fieldIndexMap(t)
+	fields := make([]crudField, 0, len(m))
+	for k, fi := range m {
+		if fi.ambiguous {
+			return nil, fmt.Errorf("%w: %q in %s", ErrFieldAmbiguous, k, t)
+		}
+		if !isPlainIdent(k) || len(k) > s.config.MaxNameLen {
+			return nil, fmt.Errorf("sqlr: column %q of %s cannot be used as a parameter name", k, t)
+		}
+		ident, err := s.crudIdent(fi.col)
+		if err != nil {
+			return nil, fmt.Errorf("%w in %s", err, t)
+		}
+		fields = append(fields, crudField{key: k, ident: ident, fieldInfo: fi})
+	}
+	slices.SortFunc(fields, func(a, b crudField) int { return slices.Compare(a.index, b.index) })
+	return fields, nil
+}
+
+// crudKeys resolves the key columns among fields: keys, or the pk fields
+// by default.
+func (s *SQLR) crudKeys(t reflect.Type, fields []crudField, keys []string) ([]crudField, error) {
+	var where []crudField
+	if len(keys) == 0 {
+		for _, f := range fields {
+			if f.pk {
+				where = append(where, f)
+			}
+		}
+		if len(where) == 0 {
+			return nil, fmt.Errorf("sqlr: %s has no `db:\",pk\"` field; pass the key columns", t)
+		}
+		return where, nil
+	}
+	for _, k := range keys {
+		i := slices.IndexFunc(fields, func(f crudField) bool { return f.key == s.mapper.key(k) })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: key %q in %s", ErrColumnNotFound, k, t)
+		}
+		where = append(where, fields[i])
+	}
+	return where, nil
+}
+
+// crudWhere writes " WHERE a = :a AND b = :b".
+func (s *SQLR) crudWhere(sb *strings.Builder, where []crudField) {
+	sb.WriteString(" WHERE ")
+	for i, f := range where {
+		if i > 0 {
+			sb.WriteString(" AND ")
+		}
+		s.crudAssign(sb, f)
+	}
+}
+
+// crudAssign writes "col = :key".
+func (s *SQLR) crudAssign(sb *strings.Builder, f crudField) {
+	sb.WriteString(f.ident)
+	sb.WriteString(" = :")
+	sb.WriteString(f.key)
+}
+
+// crudIdent returns a (possibly dotted) table or column name with every part
+// quoted for the dialect, or ErrIdentInvalid when a part is empty.
+func (s *SQLR) crudIdent(name string) (string, error) {
+	var sb strings.Builder
+	if !quoteIdent(&sb, s.spec, name) {
+		return "", fmt.Errorf("%w: %q", ErrIdentInvalid, name)
+	}
+	return sb.String(), nil
+}
+
+// crudArgs returns the values of the fields of rv as scalars, so that slice
+// fields ([]string, pq.StringArray...) reach the driver as one value instead
+// of being expanded like IN lists. They are bound after v and take precedence.
+func crudArgs(rv reflect.Value, fields ...[]crudField) P {
+	p := make(P)
+	for _, fs := range fields {
+		for _, f := range fs {
+			v, _ := getValueByPathAny(rv, f.index)
+			p[f.key] = Scalar(v)
+		}
+	}
+	return p
+}
+
+// crudZero reports whether the field f of rv holds a zero value.
+func crudZero(rv reflect.Value, f crudField) bool {
+	v, _ := getValueByPathAny(rv, f.index)
+	return isZeroValue(v)
+}
+
+// isPlainIdent reports whether name is a letter or underscore followed by
+// letters, digits and underscores.
+func isPlainIdent(name string) bool {
+	if name == "" || !isAlphaUnderscore(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isAlphaNumUnderscore(name[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+package sqlr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// crudUser exercises the pk, readonly and omitempty tag options.
+type crudUser struct {
+	ID        int       `db:"id,pk,omitempty"`
+	Name      string    `db:"name"`
+	Email     *string   `db:"email,omitempty"`
+	CreatedAt time.Time `db:"created_at,readonly"`
+	Audit     struct {
+		By string `db:"by"`
+	} `db:",prefix=audit_"`
+}
+
+// TestCRUD_Insert verifies column order, readonly/omitempty handling and
+// multi-row inserts through a rows-block.
+func TestCRUD_Insert(t *testing.T) {
+	email := "a@x.io"
+	u := crudUser{Name: "Ann", Email: &email}
+	u.Audit.By = "me"
+
+	out, args, err := New(Postgres).Insert("users", &u).Build()
+	assertNoError(t, err)
+	if out != `INSERT INTO "users" ("name", "email", "audit_by") VALUES ($1, $2, $3)` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{"Ann", &email, "me"})
+
+	u.ID, u.Email = 7, nil
+	out, args, err = New(SQLServer).Insert("dbo.user accounts", u).Write(" -- done").Build()
+	assertNoError(t, err)
+	if out != "INSERT INTO [dbo].[user accounts] ([id], [name], [audit_by]) VALUES (@p1, @p2, @p3) -- done" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{7, "Ann", "me"})
+
+	out, args, err = New(MySQL).Insert("users", []*crudUser{{ID: 1, Name: "a"}, {Name: "b"}}).Build()
+	assertNoError(t, err)
+	if out != "INSERT INTO `users` (`id`, `name`, `email`, `audit_by`) VALUES (?, ?, ?, ?), (?, ?, ?, ?)" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, "a", nil, "", 0, "b", nil, ""})
+
+	// Reserved words and mixed-case names are quoted, not case-folded.
+	type order struct {
+		CreatedAt int `db:"CreatedAt"`
+	}
+	out, _, err = New(Oracle).Insert("order", order{1}).Build()
+	assertNoError(t, err)
+	if out != `INSERT INTO "order" ("CreatedAt") VALUES (:1)` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+}
+
+// TestCRUD_UpdateDelete verifies SET/WHERE generation from pk fields or explicit
+// keys, and that the returned Builder accepts more SQL and binds.
+func TestCRUD_UpdateDelete(t *testing.T) {
+	u := crudUser{ID: 7, Name: "Ann"}
+	s := New(Postgres)
+
+	out, args, err := s.Update("users", u).Write(" AND name <> :old").Bind("old", "Bob").Build()
+	assertNoError(t, err)
+	if out != `UPDATE "users" SET "name" = $1, "audit_by" = $2 WHERE "id" = $3 AND name <> $4` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{"Ann", "", 7, "Bob"})
+
+	out, args, err = s.Update("users", &u, "name").Build()
+	assertNoError(t, err)
+	if out != `UPDATE "users" SET "audit_by" = $1 WHERE "name" = $2` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{"", "Ann"})
+
+	out, args, err = New(Oracle).Delete("users", u).Write(" RETURNING name INTO :out").Bind("out", "x").Build()
+	assertNoError(t, err)
+	if out != `DELETE FROM "users" WHERE "id" = :1 RETURNING name INTO :2` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{7, "x"})
+
+	out, _, err = s.Delete("users", u, "id", "name").Build()
+	assertNoError(t, err)
+	if out != `DELETE FROM "users" WHERE "id" = $1 AND "name" = $2` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+}
+
+// TestCRUD_SliceFields verifies that slice fields are bound as one value each,
+// never expanded like IN lists, including nil slices.
+func TestCRUD_SliceFields(t *testing.T) {
+	type post struct {
+		ID   int      `db:"id,pk"`
+		Tags []string `db:"tags"`
+		Raw  []byte   `db:"raw"`
+	}
+	p := post{ID: 1, Tags: []string{"a", "b"}}
+	s := New(Postgres)
+
+	out, args, err := s.Insert("posts", p).Build()
+	assertNoError(t, err)
+	if out != `INSERT INTO "posts" ("id", "tags", "raw") VALUES ($1, $2, $3)` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	if len(args) != 3 || len(args[1].([]string)) != 2 || args[2].([]byte) != nil {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+
+	p.Tags = nil
+	out, args, err = s.Update("posts", p).Build()
+	assertNoError(t, err)
+	if out != `UPDATE "posts" SET "tags" = $1, "raw" = $2 WHERE "id" = $3` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	if len(args) != 3 || args[0].([]string) != nil || args[2] != 1 {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+}
+
+// TestCRUD_Errors verifies invalid inputs are reported when building.
+func TestCRUD_Errors(t *testing.T) {
+	type noPK struct {
+		A int `db:"a"`
+	}
+	type onlyKey struct {
+		ID int `db:"id,pk"`
+	}
+	type badName struct {
+		A int `db:"a-b"`
+	}
+	s := New(Postgres)
+	tests := []struct {
+		b    *Builder
+		want string
+		is   error
+	}{
+		{s.Insert("t", 42), "expects a struct", nil},
+		{s.Insert("t", (*crudUser)(nil)), "expects a struct", nil},
+		{s.Insert("t", struct {
+			A int `db:"a,readonly"`
+		}{}), "no column to insert", nil},
+		{s.Update("t", noPK{}), "no `db:\",pk\"` field", nil},
+		{s.Update("t", onlyKey{}), "no column to set", nil},
+		{s.Update("t", noPK{}, "zzz"), "key \"zzz\"", ErrColumnNotFound},
+		{s.Delete("t", []noPK{}), "expects a struct", nil},
+		{s.Delete("t", badName{}, "a-b"), "cannot be used as a parameter name", nil},
+		{s.Insert("", crudUser{Name: "x"}), `invalid identifier: ""`, ErrIdentInvalid},
+		{s.Update("a..b", crudUser{ID: 1}), `"a..b"`, ErrIdentInvalid},
+		{s.Delete("t.", crudUser{ID: 1}), `"t."`, ErrIdentInvalid},
+		{s.Upsert(".t", []crudUser{{ID: 1}}, nil, nil), `".t"`, ErrIdentInvalid},
+	}
+	for i, tc := range tests {
+		_, _, err := tc.b.Build()
+		if err == nil || !strings.Contains(err.Error(), tc.want) || tc.is != nil && !errors.Is(err, tc.is) {
+			t.Fatalf("#%d: got %v, want %q", i, err, tc.want)
+		}
+	}
+}
+
+// TestCRUD_Upsert verifies the per-dialect upsert forms, default conflict/update
+// columns, and the insert-only variant.
+func TestCRUD_Upsert(t *testing.T) {
+	type stock struct {
+		SKU       string    `db:"sku,pk"`
+		Store     int       `db:"store,pk"`
+		Qty       int       `db:"qty"`
+		UpdatedAt time.Time `db:"updated_at,readonly"`
+	}
+	rows := []stock{{SKU: "a", Store: 1, Qty: 5}, {SKU: "b", Store: 1, Qty: 7}}
+	want := map[Dialect]string{
+		Postgres: `INSERT INTO "stock" ("sku", "store", "qty") VALUES ($1, $2, $3), ($4, $5, $6) ` +
+			`ON CONFLICT ("sku", "store") DO UPDATE SET "qty" = EXCLUDED."qty"`,
+		SQLite: `INSERT INTO "stock" ("sku", "store", "qty") VALUES (?, ?, ?), (?, ?, ?) ` +
+			`ON CONFLICT ("sku", "store") DO UPDATE SET "qty" = EXCLUDED."qty"`,
+		MySQL: "INSERT INTO `stock` (`sku`, `store`, `qty`) VALUES (?, ?, ?), (?, ?, ?) " +
+			"ON DUPLICATE KEY UPDATE `qty` = VALUES(`qty`)",
+		SQLServer: "MERGE INTO [stock] AS target USING (VALUES (@p1, @p2, @p3), (@p4, @p5, @p6)) AS source ([sku], [store], [qty]) " +
+			"ON target.[sku] = source.[sku] AND target.[store] = source.[store] " +
+			"WHEN MATCHED THEN UPDATE SET [qty] = source.[qty] " +
+			"WHEN NOT MATCHED THEN INSERT ([sku], [store], [qty]) VALUES (source.[sku], source.[store], source.[qty]);",
+		Oracle: `MERGE INTO "stock" target USING (VALUES (:1, :2, :3), (:4, :5, :6)) source ("sku", "store", "qty") ` +
+			`ON (target."sku" = source."sku" AND target."store" = source."store") ` +
+			`WHEN MATCHED THEN UPDATE SET target."qty" = source."qty" ` +
+			`WHEN NOT MATCHED THEN INSERT ("sku", "store", "qty") VALUES (source."sku", source."store", source."qty")`,
+	}
+	for _, dc := range allDialects() {
+		out, args, err := New(dc.d).Upsert("stock", rows, nil, nil).Build()
+		assertNoError(t, err)
+		if out != want[dc.d] {
+			t.Fatalf("[%s] unexpected SQL:\n got=%s\nwant=%s", dc.name, out, want[dc.d])
+		}
+		assertArgsEqual(t, args, []any{"a", 1, 5, "b", 1, 7})
+	}
+
+	// A single struct, explicit conflict columns and an empty update.
+	insertOnly := map[Dialect]string{
+		Postgres:  `INSERT INTO "stock" ("sku", "store", "qty") VALUES ($1, $2, $3) ON CONFLICT ("sku") DO NOTHING`,
+		MySQL:     "INSERT INTO `stock` (`sku`, `store`, `qty`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `sku` = `sku`",
+		SQLServer: "MERGE INTO [stock] AS target USING (VALUES (@p1, @p2, @p3)) AS source ([sku], [store], [qty]) ON target.[sku] = source.[sku] WHEN NOT MATCHED THEN INSERT ([sku], [store], [qty]) VALUES (source.[sku], source.[store], source.[qty]);",
+	}
+	for d, want := range insertOnly {
+		out, args, err := New(d).Upsert("stock", &rows[0], []string{"sku"}, []string{}).Build()
+		assertNoError(t, err)
+		if out != want {
+			t.Fatalf("[%s] unexpected SQL:\n got=%s\nwant=%s", d, out, want)
+		}
+		assertArgsEqual(t, args, []any{"a", 1, 5})
+	}
+
+	out, _, err := New(Postgres).Upsert("stock", rows, []string{"sku", "store"}, []string{"qty", "store"}).Build()
+	assertNoError(t, err)
+	if !strings.HasSuffix(out, `DO UPDATE SET "qty" = EXCLUDED."qty", "store" = EXCLUDED."store"`) {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+
+	if _, _, err := New(Postgres).Upsert("stock", rows, nil, []string{"updated_at"}).Build(); !errors.Is(err, ErrColumnNotFound) {
+		t.Fatalf("want ErrColumnNotFound for a readonly column, got %v", err)
+	}
+	if _, _, err := New(testDialect).Upsert("stock", rows, nil, nil).Build(); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("want unsupported dialect error, got %v", err)
+	}
+
+	// Registered dialects pick their form through Syntax.Upsert.
+	pg, _, err := New(Postgres).Upsert("stock", rows, nil, nil).Build()
+	assertNoError(t, err)
+	out, _, err = New(pgCompatDialect).Upsert("stock", rows, nil, nil).Build()
+	assertNoError(t, err)
+	if out != pg {
+		t.Fatalf("unexpected SQL:\n got=%s\nwant=%s", out, pg)
+	}
+}
@@ -0,0 +1,248 @@
+package sqlr
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DialectSpec defines how a dialect renders placeholders and identifiers and
+// which quoting and comment constructs its lexer recognizes. The built-in
+// dialects are implementations of it; custom ones are added with
+// RegisterDialect. Implementations must be safe for concurrent use.
+type DialectSpec interface {
+	// Name is the dialect name returned by Dialect.String().
+	Name() string
+	// Placeholder returns the placeholder of the idx-th arg (1-based).
+	Placeholder(idx int) string
+	// MaxParams is the default Config.MaxParams (<= 0 means unlimited).
+	MaxParams() int
+	// QuoteIdent quotes a single identifier (no dots), escaping as needed.
+	QuoteIdent(name string) string
+	// Syntax describes the lexical rules of the dialect.
+	Syntax() Syntax
+}
+
+// Syntax lists the dialect-specific lexical constructs, and the statement
+// forms the helpers render for the dialect. Single-quoted literals,
+// double-quoted identifiers, -- line comments and /* */ block comments are
+// recognized for every dialect.
+type Syntax struct {
+	HashComments bool // # line comments (MySQL)
+	Backticks    bool // `identifier` (MySQL, SQLite)
+	Brackets     bool // [identifier] (SQL Server)
+	DollarQuotes bool // $tag$ ... $tag$ blocks (Postgres)
+	QQuotes      bool // q'[ ... ]' alternative quoting (Oracle)
+	// Numbered reports that placeholders carry the arg ordinal, so an
+	// ordinal can be referenced again (Config.ReuseParams).
+	Numbered bool
+	// NamedParams reports support for @name parameters bound with
+	// sql.Named (Config.NamedArgs).
+	NamedParams bool
+	// FromDual reports that a SELECT needs a FROM clause, filled with the
+	// dual table where there is none (EmptyFalse subqueries).
+	FromDual bool
+	// Upsert is the statement rendered by SQLR.Upsert. With UpsertNone,
+	// the default, Upsert returns an error.
+	Upsert UpsertStyle
+	// Returning is how ExecReturning reads values back from a statement.
+	// With ReturningNone, the default, ExecReturning returns an error.
+	Returning ReturningStyle
+	// OrderedReturning reports that the rows returned for a multi-row
+	// INSERT ... VALUES come back in the order given, so ExecReturning can
+	// fill a slice in place.
+	OrderedReturning bool
+	// Savepoints is the form of the savepoints delimiting nested InTx
+	// transactions. With SavepointNone, the default, Tx.InTx returns an error.
+	Savepoints SavepointStyle
+}
+
+// UpsertStyle selects the insert-or-update statement of a dialect.
+type UpsertStyle uint8
+
+const (
+	UpsertNone           UpsertStyle = iota // not supported
+	UpsertOnConflict                        // INSERT ... ON CONFLICT (...) DO UPDATE SET c = EXCLUDED.c (Postgres, SQLite)
+	UpsertOnDuplicateKey                    // INSERT ... ON DUPLICATE KEY UPDATE c = VALUES(c) (MySQL)
+	UpsertMergeAs                           // MERGE INTO t AS target USING (VALUES ...) AS source ...; (SQL Server)
+	UpsertMerge                             // MERGE INTO t target USING (VALUES ...) source ON (...) (Oracle)
+)
+
+// ReturningStyle selects how a dialect returns values from the rows a
+// statement writes.
+type ReturningStyle uint8
+
+const (
+	ReturningNone     ReturningStyle = iota // not supported
+	ReturningClause                         // ... RETURNING cols (Postgres, SQLite)
+	ReturningOutput                         // OUTPUT INSERTED.cols / DELETED.cols (SQL Server)
+	ReturningInsertID                       // LastInsertId of an INSERT (MySQL)
+)
+
+// SavepointStyle selects the savepoint statements of a dialect.
+type SavepointStyle uint8
+
+const (
+	SavepointNone        SavepointStyle = iota // not supported
+	SavepointRelease                           // SAVEPOINT, ROLLBACK TO SAVEPOINT, RELEASE SAVEPOINT (Postgres, MySQL, SQLite)
+	SavepointNoRelease                         // SAVEPOINT, ROLLBACK TO SAVEPOINT, never released (Oracle)
+	SavepointTransaction                       // SAVE TRANSACTION, ROLLBACK TRANSACTION (SQL Server)
+)
+
+// --------------------------------
+// Registry
+// --------------------------------
+
+// builtinSpecs is indexed by the built-in Dialect constants.
+var builtinSpecs = [...]DialectSpec{
+	Postgres:  postgresSpec{},
+	MySQL:     mysqlSpec{},
+	SQLite:    sqliteSpec{},
+	SQLServer: sqlServerSpec{},
+	Oracle:    oracleSpec{},
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []DialectSpec // custom dialects, from len(builtinSpecs) on
+)
+
+// RegisterDialect registers a custom dialect and returns its handle for use
+// with New. Register dialects once, typically from init or main.
+func RegisterDialect(spec DialectSpec) Dialect {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, spec)
+	return Dialect(len(builtinSpecs) + len(registry) - 1)
+}
+
+// spec returns the specification of d. Unknown dialects render positional
+// ? placeholders with no dialect-specific syntax.
+func (d Dialect) spec() DialectSpec {
+	if d >= 0 && int(d) < len(builtinSpecs) {
+		return builtinSpecs[d]
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if i := int(d) - len(builtinSpecs); i >= 0 && i < len(registry) {
+		return registry[i]
+	}
+	return unknownSpec{}
+}
+
+// --------------------------------
+// Built-in dialects
+// --------------------------------
+
+type postgresSpec struct{}
+
+func (postgresSpec) Name() string   { return "postgres" }
+func (postgresSpec) MaxParams() int { return 65535 }
+func (postgresSpec) Placeholder(idx int) string {
+	return "$" + strconv.Itoa(idx)
+}
+func (postgresSpec) QuoteIdent(name string) string { return quoteWith(name, '"', '"') }
+func (postgresSpec) Syntax() Syntax {
+	return Syntax{DollarQuotes: true, Numbered: true, Upsert: UpsertOnConflict,
+		Returning: ReturningClause, OrderedReturning: true, Savepoints: SavepointRelease}
+}
+
+type mysqlSpec struct{}
+
+func (mysqlSpec) Name() string                  { return "mysql" }
+func (mysqlSpec) MaxParams() int                { return 65535 }
+func (mysqlSpec) Placeholder(int) string        { return "?" }
+func (mysqlSpec) QuoteIdent(name string) string { return quoteWith(name, '`', '`') }
+func (mysqlSpec) Syntax() Syntax {
+	return Syntax{HashComments: true, Backticks: true, DollarQuotes: true, FromDual: true,
+		Upsert: UpsertOnDuplicateKey, Returning: ReturningInsertID, Savepoints: SavepointRelease}
+}
+
+type sqliteSpec struct{}
+
+func (sqliteSpec) Name() string                  { return "sqlite" }
+func (sqliteSpec) MaxParams() int                { return 999 }
+func (sqliteSpec) Placeholder(int) string        { return "?" }
+func (sqliteSpec) QuoteIdent(name string) string { return quoteWith(name, '"', '"') }
+func (sqliteSpec) Syntax() Syntax {
+	return Syntax{Backticks: true, DollarQuotes: true, NamedParams: true, Upsert: UpsertOnConflict,
+		Returning: ReturningClause, Savepoints: SavepointRelease}
+}
+
+type sqlServerSpec struct{}
+
+func (sqlServerSpec) Name() string   { return "sqlserver" }
+func (sqlServerSpec) MaxParams() int { return 2100 }
+func (sqlServerSpec) Placeholder(idx int) string {
+	return "@p" + strconv.Itoa(idx)
+}
+func (sqlServerSpec) QuoteIdent(name string) string { return quoteWith(name, '[', ']') }
+func (sqlServerSpec) Syntax() Syntax {
+	return Syntax{Brackets: true, DollarQuotes: true, Numbered: true, NamedParams: true, Upsert: UpsertMergeAs,
+		Returning: ReturningOutput, Savepoints: SavepointTransaction}
+}
+
+type oracleSpec struct{}
+
+func (oracleSpec) Name() string   { return "oracle" }
+func (oracleSpec) MaxParams() int { return 65535 }
+func (oracleSpec) Placeholder(idx int) string {
+	return ":" + strconv.Itoa(idx)
+}
+func (oracleSpec) QuoteIdent(name string) string { return quoteWith(name, '"', '"') }
+
+// Syntax of Oracle. Binds are matched by position, so :1 is not Numbered
+// for reuse purposes.
+func (oracleSpec) Syntax() Syntax {
+	return Syntax{DollarQuotes: true, QQuotes: true, FromDual: true, Upsert: UpsertMerge,
+		Savepoints: SavepointNoRelease}
+}
+
+// unknownSpec is used for Dialect values that are neither built in nor registered.
+type unknownSpec struct{}
+
+func (unknownSpec) Name() string                  { return "unknown" }
+func (unknownSpec) MaxParams() int                { return 0 }
+func (unknownSpec) Placeholder(int) string        { return "?" }
+func (unknownSpec) QuoteIdent(name string) string { return quoteWith(name, '"', '"') }
+func (unknownSpec) Syntax() Syntax                { return Syntax{DollarQuotes: true} }
+
+// writePlaceholder writes the idx-th placeholder of spec to b. The built-in
+// dialects are rendered in place without allocating; b is never handed to a
+// custom spec, so callers' buffers can stay on the stack.
+func writePlaceholder(b *strings.Builder, spec DialectSpec, idx int) {
+	switch spec.(type) {
+	case postgresSpec:
+		writeOrdinal(b, "$", idx)
+	case sqlServerSpec:
+		writeOrdinal(b, "@p", idx)
+	case oracleSpec:
+		writeOrdinal(b, ":", idx)
+	case mysqlSpec, sqliteSpec, unknownSpec:
+		b.WriteByte('?')
+	default:
+		b.WriteString(spec.Placeholder(idx))
+	}
+}
+
+// writeOrdinal writes prefix followed by idx in decimal, without allocating.
+func writeOrdinal(b *strings.Builder, prefix string, idx int) {
+	b.WriteString(prefix)
+	var tmp [20]byte
+	b.Write(strconv.AppendInt(tmp[:0], int64(idx), 10))
+}
+
+// quoteWith wraps name in open/close, doubling any embedded close character.
+func quoteWith(name string, open, close byte) string {
+	var sb strings.Builder
+	sb.Grow(len(name) + 2)
+	sb.WriteByte(open)
+	for i := 0; i < len(name); i++ {
+		if name[i] == close {
+			sb.WriteByte(close)
+		}
+		sb.WriteByte(name[i])
+	}
+	sb.WriteByte(close)
+	return sb.String()
+}
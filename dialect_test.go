@@ -0,0 +1,92 @@
+package sqlr
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// testSpec is a custom dialect with numbered ?N placeholders and # comments.
+type testSpec struct{}
+
+func (testSpec) Name() string                  { return "testdb" }
+func (testSpec) MaxParams() int                { return 3 }
+func (testSpec) Placeholder(idx int) string    { return "?" + strconv.Itoa(idx) }
+func (testSpec) QuoteIdent(name string) string { return quoteWith(name, '"', '"') }
+func (testSpec) Syntax() Syntax {
+	return Syntax{HashComments: true, Numbered: true}
+}
+
+var testDialect = RegisterDialect(testSpec{})
+
+// pgCompatSpec is a registered Postgres-compatible dialect, such as a
+// CockroachDB wrapper: it inherits the Postgres rendering and capabilities.
+type pgCompatSpec struct{ postgresSpec }
+
+func (pgCompatSpec) Name() string { return "pgcompat" }
+
+var pgCompatDialect = RegisterDialect(pgCompatSpec{})
+
+// mysqlCompatSpec is a registered MySQL-compatible dialect, such as MariaDB.
+type mysqlCompatSpec struct{ mysqlSpec }
+
+func (mysqlCompatSpec) Name() string { return "mysqlcompat" }
+
+var mysqlCompatDialect = RegisterDialect(mysqlCompatSpec{})
+
+// TestRegisterDialect_Custom verifies that a registered dialect drives placeholder
+// rendering, lexical rules, default MaxParams, String() and ReuseParams.
+func TestRegisterDialect_Custom(t *testing.T) {
+	if testDialect.String() != "testdb" {
+		t.Fatalf("String() = %q", testDialect.String())
+	}
+	s := New(testDialect, Config{ReuseParams: true})
+	out, args, err := s.Write("SELECT :a, $x$:no$x$, :b, :a # :ignored\n").Bind("a", 1, "b", 2, "no", 3).Build()
+	assertNoError(t, err)
+	// DollarQuotes is off: the colon inside $x$...$x$ is a placeholder too.
+	if out != "SELECT ?1, $x$?2$x$, ?3, ?1 # :ignored\n" {
+		t.Fatalf("unexpected SQL: %q", out)
+	}
+	assertArgsEqual(t, args, []any{1, 3, 2})
+
+	if _, _, err := New(testDialect).Write("SELECT :a, :b, :c, :d").
+		Bind("a", 1, "b", 2, "c", 3, "d", 4).Build(); !errors.Is(err, ErrTooManyParams) {
+		t.Fatalf("expected default MaxParams=3 to apply, got %v", err)
+	}
+}
+
+// TestRegisterDialect_DistinctHandles verifies handles are distinct from built-ins and
+// unknown values still fall back to positional placeholders.
+func TestRegisterDialect_DistinctHandles(t *testing.T) {
+	for _, dc := range allDialects() {
+		if dc.d == testDialect {
+			t.Fatalf("custom handle collides with %s", dc.name)
+		}
+	}
+	out, _, err := New(Dialect(-1)).Write("SELECT :a").Bind("a", 1).Build()
+	assertNoError(t, err)
+	if out != "SELECT ?" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+}
+
+// TestDialectSpec_QuoteIdent covers identifier quoting of the built-in dialects.
+func TestDialectSpec_QuoteIdent(t *testing.T) {
+	tests := []struct {
+		d    Dialect
+		in   string
+		want string
+	}{
+		{Postgres, `user`, `"user"`},
+		{Postgres, `we"ird`, `"we""ird"`},
+		{MySQL, "or`der", "`or``der`"},
+		{SQLite, `t`, `"t"`},
+		{SQLServer, `a]b`, `[a]]b]`},
+		{Oracle, `ID`, `"ID"`},
+	}
+	for _, tc := range tests {
+		if got := tc.d.spec().QuoteIdent(tc.in); got != tc.want {
+			t.Fatalf("[%s] QuoteIdent(%q) = %q, want %q", tc.d, tc.in, got, tc.want)
+		}
+	}
+}
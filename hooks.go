@@ -0,0 +1,84 @@
+package sqlr
+
+import (
+	"context"
+	"time"
+)
+
+// Op identifies the builder operation that ran a query.
+type Op uint8
+
+const (
+	OpExec      Op = iota // Exec/ExecContext and each ExecChunked statement
+	OpScanOne             // ScanOne/ScanOneContext and One
+	OpScanAll             // ScanAll/ScanAllContext and All
+	OpRows                // Rows (streaming)
+	OpReturning           // ExecReturning
+)
+
+// String returns the name of the operation.
+func (o Op) String() string {
+	switch o {
+	case OpExec:
+		return "exec"
+	case OpScanOne:
+		return "scan_one"
+	case OpScanAll:
+		return "scan_all"
+	case OpRows:
+		return "rows"
+	case OpReturning:
+		return "returning"
+	default:
+		return "unknown"
+	}
+}
+
+// QueryInfo describes a query passed to hooks.
+type QueryInfo struct {
+	Op      Op
+	SQL     string // final SQL, as sent to the driver
+	Args    []any  // bound args; must not be modified
+	Dialect Dialect
+	// Duration is the time spent running the query, including scanning.
+	// It is only set in After.
+	Duration time.Duration
+	// Rows is set in After: rows affected for OpExec (-1 if the driver does
+	// not report it), values scanned or ids set for the other operations.
+	Rows int64
+}
+
+// Hook observes queries, e.g. for logging, metrics or tracing. Hooks are set
+// through Config.Hooks and must be safe for concurrent use.
+//
+// Before runs after the SQL is built and before it reaches the driver; the
+// returned context is used for the query and passed to After. After runs once
+// the query (and any scanning) completed, with its error, if any. Build errors
+// are reported directly and do not reach hooks.
+type Hook interface {
+	Before(ctx context.Context, info QueryInfo) context.Context
+	After(ctx context.Context, info QueryInfo, err error)
+}
+
+// hookBefore runs the Before hooks in order and returns the query context and
+// start time. Without hooks it returns ctx unchanged.
+func (s *SQLR) hookBefore(ctx context.Context, info *QueryInfo) (context.Context, time.Time) {
+	if len(s.config.Hooks) == 0 {
+		return ctx, time.Time{}
+	}
+	for _, h := range s.config.Hooks {
+		ctx = h.Before(ctx, *info)
+	}
+	return ctx, time.Now()
+}
+
+// hookAfter sets the duration and runs the After hooks in reverse order.
+func (s *SQLR) hookAfter(ctx context.Context, info *QueryInfo, start time.Time, err error) {
+	if len(s.config.Hooks) == 0 {
+		return
+	}
+	info.Duration = time.Since(start)
+	for i := len(s.config.Hooks) - 1; i >= 0; i-- {
+		s.config.Hooks[i].After(ctx, *info, err)
+	}
+}
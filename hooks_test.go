@@ -0,0 +1,133 @@
+package sqlr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type ctxKey struct{}
+
+// recHook records the calls it receives; name tags the call order.
+type recHook struct {
+	name  string
+	mu    sync.Mutex
+	calls *[]string
+	after []QueryInfo
+	errs  []error
+	ctxOK bool
+}
+
+func (h *recHook) Before(ctx context.Context, info QueryInfo) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.calls = append(*h.calls, "before:"+h.name)
+	return context.WithValue(ctx, ctxKey{}, h.name)
+}
+
+func (h *recHook) After(ctx context.Context, info QueryInfo, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.calls = append(*h.calls, "after:"+h.name)
+	h.after = append(h.after, info)
+	h.errs = append(h.errs, err)
+	h.ctxOK = ctx.Value(ctxKey{}) != nil
+}
+
+// TestHooks_ExecAndScans verifies that hooks see the final SQL, args, dialect, operation
+// kind and row counts, run Before in order and After in reverse, and share the context.
+func TestHooks_ExecAndScans(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	var calls []string
+	h1 := &recHook{name: "1", calls: &calls}
+	h2 := &recHook{name: "2", calls: &calls}
+	s := New(Postgres, Config{Hooks: []Hook{h1, h2}})
+	ctx := context.Background()
+
+	mock.ExpectExec("UPDATE").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+	_, err := s.Write("UPDATE t SET a=1 WHERE id=:id").Bind("id", 1).Exec(db)
+	assertNoError(t, err)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	var ids []int
+	assertNoError(t, s.Write("SELECT id FROM t").ScanAll(db, &ids))
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	_, err = One[int](ctx, db, s.Write("SELECT id FROM t"))
+	assertNoError(t, err)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+	for range Rows[int](ctx, db, s.Write("SELECT id FROM t")) {
+		break // early break still reports After
+	}
+
+	want := []string{"before:1", "before:2", "after:2", "after:1"}
+	if len(calls) != 16 || calls[0] != want[0] || calls[1] != want[1] || calls[2] != want[2] || calls[3] != want[3] {
+		t.Fatalf("call order: %v", calls)
+	}
+	if !h1.ctxOK {
+		t.Fatalf("After must receive the context returned by Before")
+	}
+
+	got := h1.after
+	if got[0].Op != OpExec || got[0].SQL != "UPDATE t SET a=1 WHERE id=$1" || got[0].Args[0] != 1 ||
+		got[0].Dialect != Postgres || got[0].Rows != 3 {
+		t.Fatalf("exec info: %+v", got[0])
+	}
+	if got[1].Op != OpScanAll || got[1].Rows != 2 {
+		t.Fatalf("scan all info: %+v", got[1])
+	}
+	if got[2].Op != OpScanOne || got[2].Rows != 1 {
+		t.Fatalf("scan one info: %+v", got[2])
+	}
+	if got[3].Op != OpRows || got[3].Rows != 1 {
+		t.Fatalf("rows info: %+v", got[3])
+	}
+	for _, info := range got {
+		if info.Duration <= 0 {
+			t.Fatalf("duration not set: %+v", info)
+		}
+	}
+}
+
+// TestHooks_ErrorsAndBuildFailures verifies that query errors reach After while build
+// errors do not reach hooks at all.
+func TestHooks_ErrorsAndBuildFailures(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	var calls []string
+	h := &recHook{name: "h", calls: &calls}
+	s := New(MySQL, Config{Hooks: []Hook{h}})
+
+	boom := errors.New("boom")
+	mock.ExpectQuery("SELECT").WillReturnError(boom)
+	var n int
+	if err := s.Write("SELECT 1").ScanOne(db, &n); !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if len(h.errs) != 1 || !errors.Is(h.errs[0], boom) || h.after[0].Rows != 0 {
+		t.Fatalf("After must see the query error: %v %+v", h.errs, h.after)
+	}
+
+	if _, err := s.Write("SELECT :missing").Exec(db); !errors.Is(err, ErrParamMissing) {
+		t.Fatalf("expected ErrParamMissing, got %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("build errors must not reach hooks: %v", calls)
+	}
+}
+
+// TestOp_String covers the operation names.
+func TestOp_String(t *testing.T) {
+	for op, want := range map[Op]string{OpExec: "exec", OpScanOne: "scan_one", OpScanAll: "scan_all", OpRows: "rows", Op(99): "unknown"} {
+		if op.String() != want {
+			t.Fatalf("%d: got %q, want %q", op, op.String(), want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package sqlr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LoadQueries reads named queries from the .sql files of fsys (typically an
+// embed.FS) into s, for use with Query. Without patterns, every *.sql file is
+// read, recursively; otherwise the files matching the fs.Glob patterns.
+//
+// Each query starts with a header line and runs until the next one:
+//
+//	-- name: GetUser
+//	SELECT id, name FROM users WHERE id = :id
+//
+// Blank lines and -- comments may precede the first header. Every query is
+// compiled for the dialect of s and validated: quotes and block comments must
+// be closed, :name{...} blocks well-formed and names within MaxNameLen; the
+// Line and Column of a BuildError are those of the file.
+// Loading is all-or-nothing: on error, no query of this call is registered.
+// Duplicate names, in the files or with queries already loaded, are errors.
+func (s *SQLR) LoadQueries(fsys fs.FS, patterns ...string) error {
+	var files []string
+	if len(patterns) == 0 {
+		err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && path.Ext(p) == ".sql" {
+				files = append(files, p)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, pat := range patterns {
+		matches, err := fs.Glob(fsys, pat)
+		if err != nil {
+			return err
+		}
+		files = append(files, matches...)
+	}
+
+	loaded := make(map[string]*Stmt)
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return err
+		}
+		if err := s.parseQueryFile(f, string(data), loaded); err != nil {
+			return err
+		}
+	}
+
+	s.queriesMu.Lock()
+	defer s.queriesMu.Unlock()
+	for name := range loaded {
+		if _, dup := s.queries[name]; dup {
+			return fmt.Errorf("sqlr: query %q already loaded", name)
+		}
+	}
+	if s.queries == nil {
+		s.queries = make(map[string]*Stmt, len(loaded))
+	}
+	for name, st := range loaded {
+		s.queries[name] = st
+	}
+	return nil
+}
+
+// parseQueryFile splits the content of file at its -- name: headers and
+// compiles each query into loaded.
+func (s *SQLR) parseQueryFile(file, content string, loaded map[string]*Stmt) error {
+	var (
+		name    string
+		line    int // line of the current header
+		body    strings.Builder
+		lineNum int
+	)
+	flush := func() error {
+		if name == "" {
+			return nil
+		}
+		raw := body.String()
+		q := strings.TrimSpace(raw)
+		body.Reset()
+		if q == "" {
+			return fmt.Errorf("sqlr: %s:%d: query %q is empty", file, line, name)
+		}
+		if _, dup := loaded[name]; dup {
+			return fmt.Errorf("sqlr: %s:%d: duplicate query %q", file, line, name)
+		}
+		tokens, params, err := tokenize(s, q, true)
+		if err != nil {
+			// Position the error in the file: the body starts after the
+			// header, less the blank lines and indentation trimmed from it.
+			var be *BuildError
+			if errors.As(err, &be) {
+				lead := raw[:len(raw)-len(strings.TrimLeftFunc(raw, unicode.IsSpace))]
+				if be.Line == 1 {
+					be.Column += utf8.RuneCountInString(lead[strings.LastIndexByte(lead, '\n')+1:])
+				}
+				be.Line += line + strings.Count(lead, "\n")
+			}
+			return fmt.Errorf("sqlr: %s:%d: query %q: %w", file, line, name, err)
+		}
+		loaded[name] = &Stmt{s: s, sql: q, tokens: tokens, params: params}
+		return nil
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(content))
+	sc.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for sc.Scan() {
+		lineNum++
+		text := sc.Text()
+		if n, ok := queryHeader(text); ok {
+			if err := flush(); err != nil {
+				return err
+			}
+			if n == "" {
+				return fmt.Errorf("sqlr: %s:%d: empty query name", file, lineNum)
+			}
+			name, line = n, lineNum
+			continue
+		}
+		if name == "" {
+			if t := strings.TrimSpace(text); t != "" && !strings.HasPrefix(t, "--") {
+				return fmt.Errorf("sqlr: %s:%d: SQL before the first -- name: header", file, lineNum)
+			}
+			continue
+		}
+		body.WriteString(text)
+		body.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("sqlr: %s: %w", file, err)
+	}
+	return flush()
+}
+
+// queryHeader reports whether line is a "-- name: X" header and returns X.
+func queryHeader(line string) (string, bool) {
+	t := strings.TrimSpace(line)
+	if !strings.HasPrefix(t, "--") {
+		return "", false
+	}
+	t = strings.TrimSpace(t[2:])
+	if !strings.HasPrefix(t, "name:") {
+		return "", false
+	}
+	return strings.TrimSpace(t[len("name:"):]), true
+}
+
+// Query starts a Builder for the query loaded under name by LoadQueries.
+// For an unknown name the returned Builder fails with ErrQueryNotFound.
+func (s *SQLR) Query(name string) *Builder {
+	s.queriesMu.RLock()
+	st := s.queries[name]
+	s.queriesMu.RUnlock()
+	if st == nil {
+		return s.failed(fmt.Errorf("%w: %q", ErrQueryNotFound, name))
+	}
+	b := s.Write("")
+	b.stmt = st
+	return b
+}
@@ -0,0 +1,120 @@
+package sqlr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// queriesFS is a small tree of annotated .sql files.
+var queriesFS = fstest.MapFS{
+	"users.sql": {Data: []byte(`-- Users queries.
+
+-- name: GetUser
+SELECT id, name FROM users WHERE id = :id;
+
+-- name: ListUsers
+-- optional name filter
+SELECT id, name FROM users
+WHERE 1=1 /*? AND name = :name */
+ORDER BY id
+`)},
+	"sub/orders.sql": {Data: []byte("-- name: InsertOrders\r\nINSERT INTO orders (id, total) VALUES :rows{id,total}\r\n")},
+	"README.md":      {Data: []byte("-- name: NotSQL\nSELECT 1")},
+}
+
+// TestLoadQueries_QueryBuilds verifies queries from every .sql file are loaded,
+// trimmed, and rendered through Query like compiled statements.
+func TestLoadQueries_QueryBuilds(t *testing.T) {
+	s := New(Postgres)
+	assertNoError(t, s.LoadQueries(queriesFS))
+
+	out, args, err := s.Query("GetUser").Bind("id", 7).Build()
+	assertNoError(t, err)
+	if out != "SELECT id, name FROM users WHERE id = $1;" {
+		t.Fatalf("unexpected SQL: %q", out)
+	}
+	assertArgsEqual(t, args, []any{7})
+
+	out, _, err = s.Query("ListUsers").Build()
+	assertNoError(t, err)
+	if out != "-- optional name filter\nSELECT id, name FROM users\nWHERE 1=1 \nORDER BY id" {
+		t.Fatalf("unexpected SQL: %q", out)
+	}
+
+	out, args, err = s.Query("InsertOrders").Bind("rows", []P{{"id": 1, "total": 9.5}}).Build()
+	assertNoError(t, err)
+	if out != "INSERT INTO orders (id, total) VALUES ($1, $2)" {
+		t.Fatalf("unexpected SQL: %q", out)
+	}
+	assertArgsEqual(t, args, []any{1, 9.5})
+
+	if _, _, err := s.Query("NotSQL").Build(); !errors.Is(err, ErrQueryNotFound) {
+		t.Fatalf("want ErrQueryNotFound, got %v", err)
+	}
+}
+
+// TestLoadQueries_Patterns verifies that patterns restrict the files read and that
+// loading the same names twice fails without registering anything.
+func TestLoadQueries_Patterns(t *testing.T) {
+	s := New(MySQL)
+	assertNoError(t, s.LoadQueries(queriesFS, "sub/*.sql"))
+	if _, _, err := s.Query("GetUser").Build(); !errors.Is(err, ErrQueryNotFound) {
+		t.Fatalf("want ErrQueryNotFound, got %v", err)
+	}
+	err := s.LoadQueries(queriesFS)
+	if err == nil || !strings.Contains(err.Error(), `"InsertOrders" already loaded`) {
+		t.Fatalf("want duplicate error, got %v", err)
+	}
+	if _, _, err := s.Query("GetUser").Build(); !errors.Is(err, ErrQueryNotFound) {
+		t.Fatalf("failed load registered queries: %v", err)
+	}
+}
+
+// TestLoadQueries_Validation verifies load-time errors carry the file, line and
+// query name, and wrap the parser sentinels.
+func TestLoadQueries_Validation(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+		is   error
+	}{
+		{"-- name: A\nSELECT 'open :x\n", "q.sql:1: query \"A\"", ErrUnterminated},
+		{"-- name: A\nSELECT 1\n-- name: B\nSELECT /* open\n", "q.sql:3: query \"B\"", ErrUnterminated},
+		{"-- name: A\nINSERT INTO t VALUES :rows{a,\n", "q.sql:1", ErrRowsMalformed},
+		{"-- name: A\nSELECT :a_very_long_parameter_name\n", "q.sql:1", ErrParamNameTooLong},
+		{"-- name: A\nSELECT 1\n-- name: A\nSELECT 2\n", "q.sql:3: duplicate query \"A\"", nil},
+		{"-- name: A\n\n-- name: B\nSELECT 2\n", "q.sql:1: query \"A\" is empty", nil},
+		{"SELECT 1\n-- name: A\nSELECT 2\n", "q.sql:1: SQL before", nil},
+		{"-- name:\nSELECT 2\n", "q.sql:1: empty query name", nil},
+	}
+	for _, tc := range tests {
+		s := New(Postgres, Config{MaxNameLen: 10})
+		err := s.LoadQueries(fstest.MapFS{"q.sql": {Data: []byte(tc.data)}})
+		if err == nil || !strings.Contains(err.Error(), tc.want) || tc.is != nil && !errors.Is(err, tc.is) {
+			t.Fatalf("%q: got %v, want %q (%v)", tc.data, err, tc.want, tc.is)
+		}
+	}
+
+	// Build errors are positioned in the file, past the trimmed blank lines
+	// and indentation of the query.
+	for _, tc := range []struct {
+		data         string
+		line, column int
+	}{
+		{"-- name: A\nSELECT 'open\n", 2, 8},
+		{"-- name: A\n\n  SELECT 'open\n", 3, 10},
+		{"-- name: A\nSELECT 1\n-- name: B\n\n\tSELECT 1,\n\t/* open\n", 6, 2},
+	} {
+		err := New(Postgres).LoadQueries(fstest.MapFS{"q.sql": {Data: []byte(tc.data)}})
+		var be *BuildError
+		if !errors.As(err, &be) || be.Line != tc.line || be.Column != tc.column {
+			t.Fatalf("%q: got %v, want line %d, column %d", tc.data, err, tc.line, tc.column)
+		}
+	}
+
+	// A line comment may end the file; it is not an open construct.
+	s := New(Postgres)
+	assertNoError(t, s.LoadQueries(fstest.MapFS{"q.sql": {Data: []byte("-- name: A\nSELECT 1 -- trailing")}}))
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -19,15 +21,18 @@ const (
 )
 
 var scannerIface = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+var stringType = reflect.TypeOf("")
 var scanPlanCache = newPlanCache(cacheSize)
 
 // scanOne scans the current row into dest. It supports:
 //   - pointer to Scanner types (with exactly one column)
 //   - primitives (with exactly one column)
 //   - structs (flattened mapping via `db` tags or field names)
+//   - map[string]any (keyed by column name; the map is replaced)
 //
 // It returns detailed errors when shapes mismatch.
-func scanOne(rows *sql.Rows, dest any) error {
+func scanOne(rows *sql.Rows, dest any, fm *fieldMapper) error {
 	rv := reflect.ValueOf(dest)
 	if rv.Kind() != reflect.Pointer || rv.IsNil() {
 		return fmt.Errorf("sqlr: dest must be a non-nil pointer")
@@ -45,6 +50,13 @@ func scanOne(rows *sql.Rows, dest any) error {
 		}
 		return rows.Scan(rv.Addr().Interface())
 	}
+	if isAnyMap(rv.Type()) {
+		ms, err := newMapScanner(rows, cols, fm)
+		if err != nil {
+			return err
+		}
+		return ms.read(rv)
+	}
 	if rv.Kind() != reflect.Struct {
 		if len(cols) != 1 {
 			return fmt.Errorf("sqlr: Scan on non-struct type requires 1 column, got %d", len(cols))
@@ -52,25 +64,30 @@ func scanOne(rows *sql.Rows, dest any) error {
 		return rows.Scan(rv.Addr().Interface())
 	}
 
-	return scanOneWithPlan(rows, cols, rv)
+	return scanOneWithPlan(rows, cols, rv, fm)
 }
 
 // scanOneWithPlan scans the current row into dstStruct using a cached scanPlan.
 // A per-scan state is allocated to hold mutable buffers safely.
-func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value) error {
-	plan, err := getScanPlan(cols, dstStruct.Type())
+func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value, fm *fieldMapper) error {
+	plan, err := fm.getScanPlan(cols, dstStruct.Type())
 	if err != nil {
 		return err
 	}
-	st := plan.newState()
+	return plan.newState().scanRow(rows, plan, dstStruct)
+}
 
+// scanRow scans the current row into dst (an addressable struct value) using
+// plan. The buffers in st are reused, so a single state can serve a whole
+// scan loop.
+func (st *scanState) scanRow(rows *sql.Rows, plan *scanPlan, dst reflect.Value) error {
 	// Indices of columns whose destination field is a *T where *T implements sql.Scanner.
 	// We capture the raw DB value into a sink and post-process after Scan to keep
 	// the pointer nil on NULL.
-	var ptrScannerIdx []int
+	st.ptrScannerIdx = st.ptrScannerIdx[:0]
 
-	// Prepare targets for this single row
-	for i := range cols {
+	// Prepare targets for this row
+	for i := range plan.kinds {
 		switch plan.kinds[i] {
 		case ckSink:
 			st.targets[i] = st.sinks[i]
@@ -79,9 +96,9 @@ func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value) err
 			// Use zero-alloc fast path when no intermediate pointers exist.
 			var fv reflect.Value
 			if plan.hasPtrPath[i] {
-				fv = fieldByIndexAlloc(dstStruct, plan.fPath[i])
+				fv = fieldByIndexAlloc(dst, plan.fPath[i])
 			} else {
-				fv = dstStruct.FieldByIndex(plan.fPath[i])
+				fv = dst.FieldByIndex(plan.fPath[i])
 			}
 			ft := fv.Type()
 
@@ -89,7 +106,7 @@ func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value) err
 				// Field is *T and *T implements sql.Scanner → capture raw into sink,
 				// then allocate and Scan only if non-NULL.
 				st.targets[i] = st.sinks[i]
-				ptrScannerIdx = append(ptrScannerIdx, i)
+				st.ptrScannerIdx = append(st.ptrScannerIdx, i)
 			} else {
 				// Field is T and *T implements sql.Scanner, or T itself implements Scanner:
 				// pass &T directly to rows.Scan.
@@ -99,9 +116,9 @@ func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value) err
 		case ckValue:
 			var fv reflect.Value
 			if plan.hasPtrPath[i] {
-				fv = fieldByIndexAlloc(dstStruct, plan.fPath[i])
+				fv = fieldByIndexAlloc(dst, plan.fPath[i])
 			} else {
-				fv = dstStruct.FieldByIndex(plan.fPath[i])
+				fv = dst.FieldByIndex(plan.fPath[i])
 			}
 			st.targets[i] = fv.Addr().Interface()
 
@@ -118,23 +135,23 @@ func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value) err
 
 	// Apply **ckPtr** post-assignments
 	for _, i := range plan.ptrIdx {
-		setFieldByIndex(dstStruct, plan.fPath[i], st.holders[i].Elem())
+		setFieldByIndex(dst, plan.fPath[i], st.holders[i].Elem())
 	}
 
 	// Post-process pointer-to-Scanner fields: keep nil on NULL; allocate and Scan otherwise.
-	for _, i := range ptrScannerIdx {
+	for _, i := range st.ptrScannerIdx {
 		raw := *(st.sinks[i].(*any))
 
 		var fv reflect.Value
 		if plan.hasPtrPath[i] {
-			fv = fieldByIndexAlloc(dstStruct, plan.fPath[i])
+			fv = fieldByIndexAlloc(dst, plan.fPath[i])
 		} else {
-			fv = dstStruct.FieldByIndex(plan.fPath[i])
+			fv = dst.FieldByIndex(plan.fPath[i])
 		}
 		ft := fv.Type() // *T
 
 		if raw == nil {
-			setFieldByIndex(dstStruct, plan.fPath[i], reflect.Zero(ft))
+			setFieldByIndex(dst, plan.fPath[i], reflect.Zero(ft))
 			continue
 		}
 		p := reflect.New(ft.Elem()) // *T
@@ -145,7 +162,7 @@ func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value) err
 		if err := sc.Scan(raw); err != nil {
 			return err
 		}
-		setFieldByIndex(dstStruct, plan.fPath[i], p)
+		setFieldByIndex(dst, plan.fPath[i], p)
 	}
 
 	return nil
@@ -154,9 +171,11 @@ func scanOneWithPlan(rows *sql.Rows, cols []string, dstStruct reflect.Value) err
 // scanAll scans all rows into a slice. It supports:
 //   - []T and []*T where T is struct (with column-to-field mapping)
 //   - []primitive / []Scanner (exactly one column)
-//   - []*primitive / []*Scanner (exactly one column)  // point #3
+//   - []*primitive / []*Scanner (exactly one column)
+//   - []map[string]any (one new map per row, keyed by column name)
+//   - []T / []*T where T has `db:",many"` fields (rows aggregated by pk; see aggregate.go)
 //   - SPECIAL-CASE: T is a struct that (or whose pointer) implements sql.Scanner (exactly one column)
-func scanAll(rows *sql.Rows, dest any) error {
+func scanAll(rows *sql.Rows, dest any, fm *fieldMapper) error {
 	rv := reflect.ValueOf(dest)
 	if rv.Kind() != reflect.Pointer || rv.IsNil() {
 		return fmt.Errorf("sqlr: dest must be a non-nil pointer")
@@ -170,322 +189,239 @@ func scanAll(rows *sql.Rows, dest any) error {
 	if rv.Len() != 0 {
 		rv.Set(rv.Slice(0, 0))
 	}
+	if hasMany(rv.Type().Elem()) {
+		return scanAggregate(rows, rv, fm)
+	}
 
-	elemT := rv.Type().Elem()
-	cols, err := rows.Columns()
+	rr, err := newRowReader(rows, rv.Type().Elem(), fm)
 	if err != nil {
 		return err
 	}
-
-	// ---------------------------------------------
-	// Case A: slice of pointers to NON-struct (primitive or Scanner)
-	// e.g. []*int64, []*sql.NullString (but NullString is a struct; handled later)
-	// ---------------------------------------------
-	if elemT.Kind() == reflect.Pointer && elemT.Elem().Kind() != reflect.Struct {
-		if len(cols) != 1 {
-			return fmt.Errorf("sqlr: ScanAll on slice of pointer-to-non-struct requires 1 column, got %d", len(cols))
-		}
-		for rows.Next() {
-			ptr := reflect.New(elemT.Elem()) // *T
-			if err := rows.Scan(ptr.Interface()); err != nil {
-				return err
-			}
-			// grow by reslice
-			l := rv.Len()
-			if l < rv.Cap() {
-				rv.Set(rv.Slice(0, l+1))
-			} else {
-				newCap := rv.Cap() * 2
-				if newCap == 0 {
-					newCap = 1
-				}
-				ns := reflect.MakeSlice(rv.Type(), l+1, newCap)
-				reflect.Copy(ns, rv)
-				rv.Set(ns)
-			}
-			rv.Index(l).Set(ptr)
-		}
-		return rows.Err()
-	}
-
-	// ---------------------------------------------
-	// Case B: slice of pointers to STRUCT that implements sql.Scanner (via *T or T)
-	// e.g. []*sql.NullString  (PointerTo(struct) implements Scanner)
-	// ---------------------------------------------
-	if elemT.Kind() == reflect.Pointer && elemT.Elem().Kind() == reflect.Struct {
-		structT := elemT.Elem()
-		if (reflect.PointerTo(structT).Implements(scannerIface) || structT.Implements(scannerIface)) && len(cols) == 1 {
-			for rows.Next() {
-				ptr := reflect.New(structT) // *T
-				// rows.Scan needs *T when Scanner has pointer receiver (e.g., *NullString)
-				if err := rows.Scan(ptr.Interface()); err != nil {
-					return err
-				}
-				// grow by reslice
-				l := rv.Len()
-				if l < rv.Cap() {
-					rv.Set(rv.Slice(0, l+1))
-				} else {
-					newCap := rv.Cap() * 2
-					if newCap == 0 {
-						newCap = 1
-					}
-					ns := reflect.MakeSlice(rv.Type(), l+1, newCap)
-					reflect.Copy(ns, rv)
-					rv.Set(ns)
-				}
-				rv.Index(l).Set(ptr)
-			}
-			return rows.Err()
-		}
-		// else: fall through to generic struct-mapping branch below
-	}
-
-	// ---------------------------------------------
-	// Case C: slice of STRUCT that implements sql.Scanner (via *T or T)
-	// e.g. []sql.NullString with 1 column
-	// ---------------------------------------------
-	if elemT.Kind() == reflect.Struct {
-		if (reflect.PointerTo(elemT).Implements(scannerIface) || elemT.Implements(scannerIface)) && len(cols) == 1 {
-			for rows.Next() {
-				// grow by reslice
-				l := rv.Len()
-				if l < rv.Cap() {
-					rv.Set(rv.Slice(0, l+1))
-				} else {
-					newCap := rv.Cap() * 2
-					if newCap == 0 {
-						newCap = 1
-					}
-					ns := reflect.MakeSlice(rv.Type(), l+1, newCap)
-					reflect.Copy(ns, rv)
-					rv.Set(ns)
-				}
-				dst := rv.Index(l)
-				// rows.Scan requires &T
-				if err := rows.Scan(dst.Addr().Interface()); err != nil {
-					return err
-				}
+	for rows.Next() {
+		// grow by reslice
+		l := rv.Len()
+		if l < rv.Cap() {
+			rv.Set(rv.Slice(0, l+1))
+		} else {
+			newCap := rv.Cap() * 2
+			if newCap == 0 {
+				newCap = 1
 			}
-			return rows.Err()
+			ns := reflect.MakeSlice(rv.Type(), l+1, newCap)
+			reflect.Copy(ns, rv)
+			rv.Set(ns)
+		}
+		if err := rr.read(rv.Index(l)); err != nil {
+			return err
 		}
 	}
+	return rows.Err()
+}
+
+// rowShape classifies how a rowReader scans a row into its element type.
+type rowShape uint8
+
+const (
+	rsDirect    rowShape = iota // scan the single column into &elem
+	rsNewPtr                    // allocate *T, scan the single column into it
+	rsStruct                    // struct mapping through a scanPlan
+	rsStructPtr                 // *struct mapping through a scanPlan
+	rsMap                       // map[string]any keyed by column name
+)
+
+// rowReader scans successive rows into values of a fixed element type,
+// reusing the cached scanPlan and a single scanState across rows.
+// The element type rules are the ones documented on scanAll.
+type rowReader struct {
+	rows    *sql.Rows
+	shape   rowShape
+	structT reflect.Type // for rsNewPtr/rsStructPtr: the pointed-to type
+	plan    *scanPlan
+	st      *scanState
+	ms      *mapScanner
+}
+
+// newRowReader validates the result columns against elemT and prepares the
+// scan plan when elemT is a (pointer to) struct.
+func newRowReader(rows *sql.Rows, elemT reflect.Type, fm *fieldMapper) (*rowReader, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	rr := &rowReader{rows: rows}
+
+	isScanner := func(t reflect.Type) bool {
+		return reflect.PointerTo(t).Implements(scannerIface) || t.Implements(scannerIface)
+	}
+
+	switch {
+	// Dynamic rows, e.g. []map[string]any
+	case isAnyMap(elemT):
+		ms, err := newMapScanner(rows, cols, fm)
+		if err != nil {
+			return nil, err
+		}
+		rr.shape, rr.ms = rsMap, ms
+
+	// Slice of pointers to NON-struct (primitive or Scanner), e.g. []*int64
+	case elemT.Kind() == reflect.Pointer && elemT.Elem().Kind() != reflect.Struct:
+		if len(cols) != 1 {
+			return nil, fmt.Errorf("sqlr: ScanAll on slice of pointer-to-non-struct requires 1 column, got %d", len(cols))
+		}
+		rr.shape, rr.structT = rsNewPtr, elemT.Elem()
+
+	// Pointer to STRUCT that implements sql.Scanner, e.g. []*sql.NullString
+	case elemT.Kind() == reflect.Pointer && isScanner(elemT.Elem()) && len(cols) == 1:
+		rr.shape, rr.structT = rsNewPtr, elemT.Elem()
+
+	// STRUCT that implements sql.Scanner, e.g. []sql.NullString
+	case elemT.Kind() == reflect.Struct && isScanner(elemT) && len(cols) == 1:
+		rr.shape = rsDirect
 
-	// ---------------------------------------------
 	// Generic struct / *struct mapping
-	// ---------------------------------------------
-	switch elemT.Kind() {
-	case reflect.Struct, reflect.Pointer:
-		var structT reflect.Type
-		isPtr := elemT.Kind() == reflect.Pointer
-		if isPtr {
-			if elemT.Elem().Kind() != reflect.Struct {
-				// Should not happen due to cases above, but keep a defensive error.
-				return fmt.Errorf("sqlr: slice of pointers to non-struct")
-			}
+	case elemT.Kind() == reflect.Struct || elemT.Kind() == reflect.Pointer:
+		structT := elemT
+		rr.shape = rsStruct
+		if elemT.Kind() == reflect.Pointer {
 			structT = elemT.Elem()
-		} else {
-			structT = elemT
+			rr.shape, rr.structT = rsStructPtr, structT
 		}
-
-		plan, err := getScanPlan(cols, structT)
+		if hasMany(structT) {
+			return nil, fmt.Errorf("sqlr: %s has `db:\",many\"` fields; rows are aggregated only by ScanOne/ScanAll", structT)
+		}
+		plan, err := fm.getScanPlan(cols, structT)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		st := plan.newState()
+		rr.plan, rr.st = plan, plan.newState()
 
-		// Reusable list for pointer-to-Scanner fields
-		var ptrScannerIdx []int
+	// Primitive/Scanner (non-struct) → must be 1 column
+	default:
+		if len(cols) != 1 {
+			return nil, fmt.Errorf("sqlr: ScanAll on slice of non-struct requires 1 column, got %d", len(cols))
+		}
+		rr.shape = rsDirect
+	}
+	return rr, nil
+}
 
-		for rows.Next() {
-			// grow by reslice
-			l := rv.Len()
-			if l < rv.Cap() {
-				rv.Set(rv.Slice(0, l+1))
-			} else {
-				newCap := rv.Cap() * 2
-				if newCap == 0 {
-					newCap = 1
-				}
-				ns := reflect.MakeSlice(rv.Type(), l+1, newCap)
-				reflect.Copy(ns, rv)
-				rv.Set(ns)
-			}
-			idx := l
-
-			if isPtr {
-				// []*T: create *T, scan into its Elem, then assign ptr
-				ptr := reflect.New(structT)
-				dst := ptr.Elem()
-
-				ptrScannerIdx = ptrScannerIdx[:0] // reset
-
-				// Prepare targets
-				for i := range cols {
-					switch plan.kinds[i] {
-					case ckSink:
-						st.targets[i] = st.sinks[i]
-					case ckScanner:
-						var fv reflect.Value
-						if plan.hasPtrPath[i] {
-							fv = fieldByIndexAlloc(dst, plan.fPath[i])
-						} else {
-							fv = dst.FieldByIndex(plan.fPath[i])
-						}
-						ft := fv.Type()
-						if ft.Kind() == reflect.Pointer && ft.Implements(scannerIface) {
-							st.targets[i] = st.sinks[i]
-							ptrScannerIdx = append(ptrScannerIdx, i)
-						} else {
-							st.targets[i] = fv.Addr().Interface()
-						}
-					case ckValue:
-						var fv reflect.Value
-						if plan.hasPtrPath[i] {
-							fv = fieldByIndexAlloc(dst, plan.fPath[i])
-						} else {
-							fv = dst.FieldByIndex(plan.fPath[i])
-						}
-						st.targets[i] = fv.Addr().Interface()
-					case ckPtr:
-						h := st.holders[i]
-						h.Elem().SetZero()
-						st.targets[i] = h.Interface()
-					}
-				}
-
-				if err := rows.Scan(st.targets...); err != nil {
-					return err
-				}
-				for _, i := range plan.ptrIdx {
-					setFieldByIndex(dst, plan.fPath[i], st.holders[i].Elem())
-				}
-				// Post-process pointer-to-Scanner fields
-				for _, i := range ptrScannerIdx {
-					raw := *(st.sinks[i].(*any))
-					var fv reflect.Value
-					if plan.hasPtrPath[i] {
-						fv = fieldByIndexAlloc(dst, plan.fPath[i])
-					} else {
-						fv = dst.FieldByIndex(plan.fPath[i])
-					}
-					ft := fv.Type() // *T
-					if raw == nil {
-						setFieldByIndex(dst, plan.fPath[i], reflect.Zero(ft))
-						continue
-					}
-					p := reflect.New(ft.Elem()) // *T
-					sc, ok := p.Interface().(sql.Scanner)
-					if !ok {
-						return fmt.Errorf("sqlr: internal: %v does not implement sql.Scanner", ft)
-					}
-					if err := sc.Scan(raw); err != nil {
-						return err
-					}
-					setFieldByIndex(dst, plan.fPath[i], p)
-				}
-
-				rv.Index(idx).Set(ptr)
+// read scans the current row into dst, an addressable value of the element type.
+func (rr *rowReader) read(dst reflect.Value) error {
+	switch rr.shape {
+	case rsNewPtr:
+		ptr := reflect.New(rr.structT) // *T
+		if err := rr.rows.Scan(ptr.Interface()); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
 
-			} else {
-				// []T: scan directly into the new element.
-				dst := rv.Index(idx)
-
-				ptrScannerIdx = ptrScannerIdx[:0] // reset
-
-				for i := range cols {
-					switch plan.kinds[i] {
-					case ckSink:
-						st.targets[i] = st.sinks[i]
-					case ckScanner:
-						var fv reflect.Value
-						if plan.hasPtrPath[i] {
-							fv = fieldByIndexAlloc(dst, plan.fPath[i])
-						} else {
-							fv = dst.FieldByIndex(plan.fPath[i])
-						}
-						ft := fv.Type()
-						if ft.Kind() == reflect.Pointer && ft.Implements(scannerIface) {
-							st.targets[i] = st.sinks[i]
-							ptrScannerIdx = append(ptrScannerIdx, i)
-						} else {
-							st.targets[i] = fv.Addr().Interface()
-						}
-					case ckValue:
-						var fv reflect.Value
-						if plan.hasPtrPath[i] {
-							fv = fieldByIndexAlloc(dst, plan.fPath[i])
-						} else {
-							fv = dst.FieldByIndex(plan.fPath[i])
-						}
-						st.targets[i] = fv.Addr().Interface()
-					case ckPtr:
-						h := st.holders[i]
-						h.Elem().SetZero()
-						st.targets[i] = h.Interface()
-					}
-				}
-
-				if err := rows.Scan(st.targets...); err != nil {
-					return err
-				}
-				for _, i := range plan.ptrIdx {
-					setFieldByIndex(dst, plan.fPath[i], st.holders[i].Elem())
-				}
-				// Post-process pointer-to-Scanner fields
-				for _, i := range ptrScannerIdx {
-					raw := *(st.sinks[i].(*any))
-					var fv reflect.Value
-					if plan.hasPtrPath[i] {
-						fv = fieldByIndexAlloc(dst, plan.fPath[i])
-					} else {
-						fv = dst.FieldByIndex(plan.fPath[i])
-					}
-					ft := fv.Type() // *T
-					if raw == nil {
-						setFieldByIndex(dst, plan.fPath[i], reflect.Zero(ft))
-						continue
-					}
-					p := reflect.New(ft.Elem()) // *T
-					sc, ok := p.Interface().(sql.Scanner)
-					if !ok {
-						return fmt.Errorf("sqlr: internal: %v does not implement sql.Scanner", ft)
-					}
-					if err := sc.Scan(raw); err != nil {
-						return err
-					}
-					setFieldByIndex(dst, plan.fPath[i], p)
-				}
-			}
+	case rsStruct:
+		return rr.st.scanRow(rr.rows, rr.plan, dst)
+
+	case rsStructPtr:
+		// []*T: create *T, scan into its Elem, then assign ptr
+		ptr := reflect.New(rr.structT)
+		if err := rr.st.scanRow(rr.rows, rr.plan, ptr.Elem()); err != nil {
+			return err
 		}
-		return rows.Err()
+		dst.Set(ptr)
+		return nil
+
+	case rsMap:
+		return rr.ms.read(dst)
 
 	default:
-		// Primitive/Scanner (non-struct) → must be 1 column
-		if len(cols) != 1 {
-			return fmt.Errorf("sqlr: ScanAll on slice of non-struct requires 1 column, got %d", len(cols))
+		return rr.rows.Scan(dst.Addr().Interface())
+	}
+}
+
+// isAnyMap reports whether t is a map with string keys and `any` values,
+// such as map[string]any.
+func isAnyMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem() == anyType
+}
+
+// mapScanner scans rows of unknown shape into map[string]any values keyed by
+// column name. When two columns share a name, the last one wins.
+type mapScanner struct {
+	rows     *sql.Rows
+	cols     []string
+	vals     []any
+	targets  []any
+	asString []bool // per column: convert []byte values to string
+}
+
+// newMapScanner prepares the scan buffers for cols. When the mapper converts
+// bytes to strings, the column types decide which columns are textual.
+func newMapScanner(rows *sql.Rows, cols []string, fm *fieldMapper) (*mapScanner, error) {
+	ms := &mapScanner{
+		rows:    rows,
+		cols:    cols,
+		vals:    make([]any, len(cols)),
+		targets: make([]any, len(cols)),
+	}
+	for i := range ms.vals {
+		ms.targets[i] = &ms.vals[i]
+	}
+	if fm.bytesToString {
+		cts, err := rows.ColumnTypes()
+		if err != nil {
+			return nil, err
 		}
-		for rows.Next() {
-			// grow by reslice
-			l := rv.Len()
-			if l < rv.Cap() {
-				rv.Set(rv.Slice(0, l+1))
-			} else {
-				newCap := rv.Cap() * 2
-				if newCap == 0 {
-					newCap = 1
-				}
-				ns := reflect.MakeSlice(rv.Type(), l+1, newCap)
-				reflect.Copy(ns, rv)
-				rv.Set(ns)
-			}
-			item := rv.Index(l)
-			if err := rows.Scan(item.Addr().Interface()); err != nil {
-				return err
-			}
+		ms.asString = make([]bool, len(cols))
+		for i, ct := range cts {
+			ms.asString[i] = !isBinaryType(ct.DatabaseTypeName())
 		}
-		return rows.Err()
 	}
+	return ms, nil
+}
+
+// read scans the current row into a new map and stores it in dst.
+func (ms *mapScanner) read(dst reflect.Value) error {
+	if err := ms.rows.Scan(ms.targets...); err != nil {
+		return err
+	}
+	// Maps keyed by a named string type (map[K]any) cannot be converted from
+	// map[string]any, so they are filled through reflection.
+	var rm reflect.Value
+	var m map[string]any
+	if dst.Type().Key() == stringType {
+		m = make(map[string]any, len(ms.cols))
+	} else {
+		rm = reflect.MakeMapWithSize(dst.Type(), len(ms.cols))
+	}
+	for i, col := range ms.cols {
+		v := ms.vals[i]
+		// database/sql hands *any targets a private copy of []byte, so the
+		// value can be kept (or converted) without copying again.
+		if b, ok := v.([]byte); ok && ms.asString != nil && ms.asString[i] {
+			v = string(b)
+		}
+		if m != nil {
+			m[col] = v
+		} else {
+			rm.SetMapIndex(reflect.ValueOf(col).Convert(dst.Type().Key()), reflect.ValueOf(&v).Elem())
+		}
+		ms.vals[i] = nil
+	}
+	if m != nil {
+		rm = reflect.ValueOf(m).Convert(dst.Type())
+	}
+	dst.Set(rm)
+	return nil
+}
+
+// isBinaryType reports whether a driver's database type name denotes binary
+// data (BLOB, BYTEA, [VAR]BINARY, IMAGE, RAW...), which stays []byte.
+func isBinaryType(name string) bool {
+	name = strings.ToUpper(name)
+	for _, t := range [...]string{"BLOB", "BYTEA", "BINARY", "IMAGE", "RAW"} {
+		if strings.Contains(name, t) {
+			return true
+		}
+	}
+	return false
 }
 
 // fieldByIndexAlloc walks a struct by index path, allocating intermediate
@@ -567,14 +503,14 @@ func hasPtrOnPath(rootT reflect.Type, path []int) bool {
 
 // buildScanPlan builds an immutable scanPlan describing how each result column
 // should be scanned into the destination struct type dstT.
-func buildScanPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
+func (fm *fieldMapper) buildScanPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
 	// Normalize destination type (we operate on the concrete struct type).
 	for dstT.Kind() == reflect.Pointer {
 		dstT = dstT.Elem()
 	}
 
 	// Field map: column name -> fieldInfo (flattened path, ambiguity, scalar flag).
-	fmap := fieldIndexMap(dstT)
+	fmap := fm.fieldIndexMap(dstT)
 
 	p := &scanPlan{
 		kinds:         make([]colKind, len(cols)),
@@ -585,7 +521,7 @@ func buildScanPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
 	}
 
 	for i, col := range cols {
-		fi, ok := fmap[col]
+		fi, ok := fmap[fm.key(col)]
 		if !ok {
 			// Column not mapped to any field -> sink it.
 			p.kinds[i] = ckSink
@@ -628,6 +564,60 @@ func buildScanPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
 	return p, nil
 }
 
+// checkStrict applies the StrictColumns/StrictFields rules to a plan built
+// for cols and the struct type dstT, and records their errors in the plan.
+func (fm *fieldMapper) checkStrict(cols []string, dstT reflect.Type, p *scanPlan) {
+	if fm.strictCols {
+		var unmapped []string
+		for i, k := range p.kinds {
+			if k == ckSink {
+				unmapped = append(unmapped, cols[i])
+			}
+		}
+		if len(unmapped) > 0 {
+			p.unmappedErr = fmt.Errorf("%w: %s (into %s)", ErrColumnUnmapped, quoteList(unmapped), dstT)
+		}
+	}
+	if fm.strictFields {
+		p.unfilledErr = fm.checkFieldsFilled(cols, dstT)
+	}
+}
+
+// checkFieldsFilled reports the tagged fields of dstT that no column in cols maps to.
+func (fm *fieldMapper) checkFieldsFilled(cols []string, dstT reflect.Type) error {
+	fmap := fm.fieldIndexMap(dstT)
+	seen := make(map[string]struct{}, len(cols))
+	for _, col := range cols {
+		seen[fm.key(col)] = struct{}{}
+	}
+	var missing []string
+	for name, fi := range fmap {
+		if !fi.tagged || fi.ambiguous {
+			continue
+		}
+		if _, ok := seen[name]; !ok {
+			missing = append(missing, dstT.FieldByIndex(fi.index).Name+" ("+name+")")
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("%w: %s (from %s)", ErrFieldUnfilled, strings.Join(missing, ", "), dstT)
+}
+
+// quoteList renders names as a comma-separated list of quoted strings.
+func quoteList(names []string) string {
+	var sb strings.Builder
+	for i, n := range names {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.Quote(n))
+	}
+	return sb.String()
+}
+
 // --------------------------------
 // Cache
 // --------------------------------
@@ -635,9 +625,10 @@ func buildScanPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
 // scanState holds per-scan mutable buffers.
 // It is created from a cached scanPlan and is not shared across goroutines.
 type scanState struct {
-	targets []any
-	sinks   []any
-	holders []reflect.Value
+	targets       []any
+	sinks         []any
+	holders       []reflect.Value
+	ptrScannerIdx []int // per-row list of pointer-to-Scanner columns
 }
 
 // scanPlan describes how to map each result column to a struct field (immutable).
@@ -648,6 +639,8 @@ type scanPlan struct {
 	ptrIdx        []int
 	ptrFieldTypes []reflect.Type // for ckPtr: field reflect.Type (which is a pointer type *T)
 	hasPtrPath    []bool         // for each column, whether the index path has intermediate pointers
+	unmappedErr   error          // StrictColumns: ErrColumnUnmapped for cols, checked once
+	unfilledErr   error          // StrictFields: ErrFieldUnfilled for cols, checked once
 }
 
 // newState allocates per-scan buffers sized to the plan's column count.
@@ -687,12 +680,14 @@ type planCache struct {
 }
 
 // newPlanCache creates a new two-tier plan cache with a max size hint.
+// The maps start empty and grow with use, so short-lived SQLR instances stay
+// cheap.
 func newPlanCache(max int) *planCache {
 	if max <= 0 {
 		max = cacheSize
 	}
 	return &planCache{
-		curr: make(map[planKey]*scanPlan, max/2),
+		curr: make(map[planKey]*scanPlan),
 		prev: make(map[planKey]*scanPlan),
 		max:  max,
 	}
@@ -765,18 +760,41 @@ func canonicalStructType(t reflect.Type) reflect.Type {
 	return t
 }
 
-// getScanPlan returns a cached scanPlan for (dst struct type, cols), or builds and caches it.
+// getScanPlan returns a cached scanPlan for (dst struct type, cols), or builds and caches it,
+// and fails if it breaks the mapper's strict rules.
 // The returned plan is immutable and safe for concurrent reuse.
-func getScanPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
+func (fm *fieldMapper) getScanPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
+	p, err := fm.cachedPlan(cols, dstT)
+	if err != nil {
+		return nil, err
+	}
+	if p.unmappedErr != nil {
+		return nil, p.unmappedErr
+	}
+	if p.unfilledErr != nil {
+		return nil, p.unfilledErr
+	}
+	return p, nil
+}
+
+// cachedPlan returns the scanPlan for (dst struct type, cols) without failing
+// on strict checks: they run once, when the plan is built, and their errors
+// are kept in the plan. Plans are cached per mapper.
+func (fm *fieldMapper) cachedPlan(cols []string, dstT reflect.Type) (*scanPlan, error) {
+	cache := fm.plans
+	if cache == nil {
+		cache = scanPlanCache
+	}
 	dstT = canonicalStructType(dstT)
 	key := planKey{dstType: dstT, sig: columnsSignature(cols)}
-	if p, ok := scanPlanCache.get(key); ok {
+	if p, ok := cache.get(key); ok {
 		return p, nil
 	}
-	p, err := buildScanPlan(cols, dstT)
+	p, err := fm.buildScanPlan(cols, dstT)
 	if err != nil {
 		return nil, err
 	}
-	scanPlanCache.put(key, p)
+	fm.checkStrict(cols, dstT, p)
+	cache.put(key, p)
 	return p, nil
 }
@@ -25,6 +25,8 @@ func placeholderRegexMapper(d Dialect) *regexp.Regexp {
 		return regexp.MustCompile(`\$(?:[1-9][0-9]*)`)
 	case SQLServer:
 		return regexp.MustCompile(`@p(?:[1-9][0-9]*)`)
+	case Oracle:
+		return regexp.MustCompile(`:(?:[1-9][0-9]*)`)
 	default:
 		return regexp.MustCompile(`\?`)
 	}
@@ -1343,7 +1345,7 @@ func TestBuildScanPlan_AmbiguousField_Direct(t *testing.T) {
 	}
 	cols := []string{"id"}
 
-	_, err := buildScanPlan(cols, reflect.TypeOf(Amb{}))
+	_, err := defaultMapper.buildScanPlan(cols, reflect.TypeOf(Amb{}))
 	if err == nil || !errors.Is(err, ErrFieldAmbiguous) {
 		t.Fatalf("expected ErrFieldAmbiguous, got: %v", err)
 	}
@@ -1358,11 +1360,11 @@ func TestGetScanPlan_CacheHitSamePointer(t *testing.T) {
 	}
 	cols := []string{"id", "name"}
 
-	p1, err := getScanPlan(cols, reflect.TypeOf(Row{}))
+	p1, err := defaultMapper.getScanPlan(cols, reflect.TypeOf(Row{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	p2, err := getScanPlan(cols, reflect.TypeOf(Row{}))
+	p2, err := defaultMapper.getScanPlan(cols, reflect.TypeOf(Row{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1381,11 +1383,11 @@ func TestGetScanPlan_DifferentColumnsDifferentPlan(t *testing.T) {
 	cols1 := []string{"id", "name"}
 	cols2 := []string{"name", "id"} // order matters
 
-	p1, err := getScanPlan(cols1, reflect.TypeOf(Row{}))
+	p1, err := defaultMapper.getScanPlan(cols1, reflect.TypeOf(Row{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	p2, err := getScanPlan(cols2, reflect.TypeOf(Row{}))
+	p2, err := defaultMapper.getScanPlan(cols2, reflect.TypeOf(Row{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1402,11 +1404,11 @@ func TestGetScanPlan_PointerAndValueSamePlan(t *testing.T) {
 	}
 	cols := []string{"id"}
 
-	pVal, err := getScanPlan(cols, reflect.TypeOf(Row{}))
+	pVal, err := defaultMapper.getScanPlan(cols, reflect.TypeOf(Row{}))
 	if err != nil {
 		t.Fatalf("unexpected error (value): %v", err)
 	}
-	pPtr, err := getScanPlan(cols, reflect.TypeOf(&Row{}))
+	pPtr, err := defaultMapper.getScanPlan(cols, reflect.TypeOf(&Row{}))
 	if err != nil {
 		t.Fatalf("unexpected error (pointer): %v", err)
 	}
@@ -1431,7 +1433,7 @@ func TestGetScanPlan_Concurrent(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			p, err := getScanPlan(cols, reflect.TypeOf(Row{}))
+			p, err := defaultMapper.getScanPlan(cols, reflect.TypeOf(Row{}))
 			if err != nil {
 				errCh <- err
 				return
@@ -1644,6 +1646,622 @@ func TestBind_EmbeddedPointerStruct_DerefAndNil_AllDialects(t *testing.T) {
 	}
 }
 
+// --------------------------------
+// Generic helpers: One[T], All[T]
+// --------------------------------
+
+// TestOne_Struct_And_Primitive verifies One[T] returns a scanned struct or primitive.
+func TestOne_Struct_And_Primitive(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	mock.ExpectQuery(".*").WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "x"))
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(42))
+
+	s := New(Postgres)
+	r, err := One[Row](context.Background(), db, s.Write("SELECT id, name FROM t WHERE id=:id").Bind("id", 7))
+	assertNoError(t, err)
+	if r.ID != 7 || r.Name != "x" {
+		t.Fatalf("got %+v", r)
+	}
+
+	n, err := One[int](context.Background(), db, s.Write("SELECT COUNT(*) FROM t"))
+	assertNoError(t, err)
+	if n != 42 {
+		t.Fatalf("got %d, want 42", n)
+	}
+}
+
+// TestOne_Errors_ReturnZero verifies One[T] propagates sql.ErrNoRows and ErrMoreThanOneRow
+// and returns the zero value on error.
+func TestOne_Errors_ReturnZero(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"v"}))
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(1).AddRow(2))
+
+	s := New(MySQL)
+	if _, err := One[int](context.Background(), db, s.Write("SELECT v FROM t")); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+	v, err := One[int](context.Background(), db, s.Write("SELECT v FROM t"))
+	if !errors.Is(err, ErrMoreThanOneRow) {
+		t.Fatalf("want ErrMoreThanOneRow, got %v", err)
+	}
+	if v != 0 {
+		t.Fatalf("want zero value on error, got %d", v)
+	}
+}
+
+// TestAll_Structs_Pointers_Primitives verifies All[T] for struct, *struct and primitive T.
+func TestAll_Structs_Pointers_Primitives(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		ID int `db:"id"`
+	}
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5))
+
+	ctx := context.Background()
+	s := New(SQLite)
+
+	rows, err := All[Row](ctx, db, s.Write("SELECT id FROM t"))
+	assertNoError(t, err)
+	if len(rows) != 2 || rows[0].ID != 1 || rows[1].ID != 2 {
+		t.Fatalf("got %+v", rows)
+	}
+
+	ptrs, err := All[*Row](ctx, db, s.Write("SELECT id FROM t"))
+	assertNoError(t, err)
+	if len(ptrs) != 1 || ptrs[0].ID != 3 {
+		t.Fatalf("got %+v", ptrs)
+	}
+
+	ids, err := All[int64](ctx, db, s.Write("SELECT id FROM t"))
+	assertNoError(t, err)
+	if len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+		t.Fatalf("got %v", ids)
+	}
+}
+
+// TestAll_NoRows_And_BuildError verifies All[T] returns nil for an empty result and
+// surfaces build errors without querying.
+func TestAll_NoRows_And_BuildError(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ctx := context.Background()
+	s := New(Postgres)
+	out, err := All[int](ctx, db, s.Write("SELECT id FROM t"))
+	assertNoError(t, err)
+	if out != nil {
+		t.Fatalf("want nil slice, got %v", out)
+	}
+
+	if _, err := All[int](ctx, db, s.Write("SELECT :missing")); !errors.Is(err, ErrParamMissing) {
+		t.Fatalf("want ErrParamMissing, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// --------------------------------
+// Streaming: Rows[T]
+// --------------------------------
+
+// TestRows_Struct_StreamsAllRows verifies Rows[T] yields every row in order and resets the
+// reused holder between rows.
+func TestRows_Struct_StreamsAllRows(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		ID    int     `db:"id"`
+		Note  *string `db:"note"`
+		Extra string  // never mapped: must stay zero
+	}
+	mock.ExpectQuery(".*").WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "note"}).
+			AddRow(1, "a").
+			AddRow(2, nil).
+			AddRow(3, "c")).
+		RowsWillBeClosed()
+
+	var got []Row
+	for r, err := range Rows[Row](context.Background(), db, New(Postgres).Write("SELECT id, note FROM t WHERE a=:a").Bind("a", true)) {
+		assertNoError(t, err)
+		got = append(got, r)
+	}
+	if len(got) != 3 || got[0].ID != 1 || got[2].ID != 3 {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+	if got[0].Note == nil || *got[0].Note != "a" || got[1].Note != nil || *got[2].Note != "c" {
+		t.Fatalf("pointer fields not reset per row: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRows_EarlyBreak_ClosesRows verifies breaking out of the loop closes *sql.Rows.
+func TestRows_EarlyBreak_ClosesRows(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(1).AddRow(2).AddRow(3)).
+		RowsWillBeClosed()
+
+	n := 0
+	for v, err := range Rows[int](context.Background(), db, New(SQLite).Write("SELECT v FROM t")) {
+		assertNoError(t, err)
+		n++
+		if v == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Fatalf("iterated %d rows, want 2", n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRows_PtrStruct_DistinctPointers verifies Rows[*T] yields a fresh pointer per row.
+func TestRows_PtrStruct_DistinctPointers(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		ID int `db:"id"`
+	}
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	var got []*Row
+	for r, err := range Rows[*Row](context.Background(), db, New(MySQL).Write("SELECT id FROM t")) {
+		assertNoError(t, err)
+		got = append(got, r)
+	}
+	if len(got) != 2 || got[0] == got[1] || got[0].ID != 1 || got[1].ID != 2 {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+}
+
+// TestRows_Errors verifies build, shape and row errors are yielded once and stop iteration.
+func TestRows_Errors(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	s := New(Postgres)
+
+	// Build error: no query is executed.
+	n := 0
+	for _, err := range Rows[int](ctx, db, s.Write("SELECT :missing")) {
+		n++
+		if !errors.Is(err, ErrParamMissing) {
+			t.Fatalf("want ErrParamMissing, got %v", err)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("build error yielded %d times, want 1", n)
+	}
+
+	// Shape error: primitive destination with two columns.
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"a", "b"}).AddRow(1, 2))
+	for _, err := range Rows[int](ctx, db, s.Write("SELECT a, b FROM t")) {
+		if err == nil || !strings.Contains(err.Error(), "requires 1 column") {
+			t.Fatalf("want column count error, got %v", err)
+		}
+	}
+
+	// Row error after the first row.
+	boom := errors.New("boom")
+	mock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(1).AddRow(2).RowError(1, boom))
+	var vals []int
+	var last error
+	for v, err := range Rows[int](ctx, db, s.Write("SELECT v FROM t")) {
+		if err != nil {
+			last = err
+			continue
+		}
+		vals = append(vals, v)
+	}
+	if len(vals) != 1 || !errors.Is(last, boom) {
+		t.Fatalf("vals=%v err=%v, want [1] and boom", vals, last)
+	}
+}
+
+// --------------------------------
+// Name mapping
+// --------------------------------
+
+// TestSnakeCase covers common Go naming patterns, including initialisms.
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"CreatedAt":  "created_at",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Address2":   "address2",
+		"already_ok": "already_ok",
+		"Foo_Bar":    "foo_bar",
+		"":           "",
+	}
+	for in, want := range tests {
+		if got := SnakeCase(in); got != want {
+			t.Fatalf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestNameMapper_SnakeCase_ScanAndBind verifies that untagged fields are matched through
+// the configured NameMapper for scans, :name binds and :name{...} blocks, while tags win.
+func TestNameMapper_SnakeCase_ScanAndBind(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		UserID    int
+		CreatedAt string
+		Label     string `db:"lbl"`
+	}
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at", "lbl"}).AddRow(7, "today", "x"))
+
+	s := New(Postgres, Config{NameMapper: SnakeCase})
+	var r Row
+	assertNoError(t, s.Write("SELECT user_id, created_at, lbl FROM t").ScanOne(db, &r))
+	if r.UserID != 7 || r.CreatedAt != "today" || r.Label != "x" {
+		t.Fatalf("got %+v", r)
+	}
+
+	out, args, err := s.Write("SELECT :user_id, :lbl").Bind(Row{UserID: 1, Label: "l"}).Build()
+	assertNoError(t, err)
+	if out != "SELECT $1, $2" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, "l"})
+
+	_, args, err = s.Write("INSERT INTO t VALUES :rows{user_id,created_at}").
+		Bind("rows", []Row{{UserID: 1, CreatedAt: "a"}, {UserID: 2, CreatedAt: "b"}}).Build()
+	assertNoError(t, err)
+	assertArgsEqual(t, args, []any{1, "a", 2, "b"})
+
+	// The default mapper is unaffected.
+	if _, _, err := New(Postgres).Write("SELECT :user_id").Bind(Row{}).Build(); !errors.Is(err, ErrParamMissing) {
+		t.Fatalf("default mapper must not snake_case fields, got %v", err)
+	}
+}
+
+// TestNameMapper_CaseInsensitive verifies columns returned in a different case are matched.
+func TestNameMapper_CaseInsensitive(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		ID   int    `db:"id"`
+		Name string // untagged: matched as "Name" in any case
+	}
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "NAME"}).AddRow(1, "a").AddRow(2, "b"))
+
+	s := New(SQLServer, Config{CaseInsensitive: true})
+	var out []Row
+	assertNoError(t, s.Write("SELECT ID, NAME FROM t").ScanAll(db, &out))
+	if len(out) != 2 || out[0].ID != 1 || out[1].Name != "b" {
+		t.Fatalf("got %+v", out)
+	}
+
+	_, args, err := s.Write("SELECT :Id, :NAME").Bind(Row{ID: 3, Name: "c"}).Build()
+	assertNoError(t, err)
+	assertArgsEqual(t, args, []any{3, "c"})
+}
+
+// TestNameMapper_CachesArePerMapper ensures two SQLR instances with different mapping rules
+// do not share cached field maps or scan plans for the same type and columns.
+func TestNameMapper_CachesArePerMapper(t *testing.T) {
+	type Row struct {
+		CreatedAt string
+	}
+	snake := New(Postgres, Config{NameMapper: SnakeCase})
+	plain := New(Postgres)
+
+	if _, ok := snake.mapper.fieldIndexMap(reflect.TypeOf(Row{}))["created_at"]; !ok {
+		t.Fatalf("snake mapper: created_at not mapped")
+	}
+	if _, ok := plain.mapper.fieldIndexMap(reflect.TypeOf(Row{}))["created_at"]; ok {
+		t.Fatalf("default mapper must not see snake_case names")
+	}
+
+	cols := []string{"created_at"}
+	p1, err := snake.mapper.getScanPlan(cols, reflect.TypeOf(Row{}))
+	assertNoError(t, err)
+	p2, err := plain.mapper.getScanPlan(cols, reflect.TypeOf(Row{}))
+	assertNoError(t, err)
+	if p1 == p2 || p1.kinds[0] != ckValue || p2.kinds[0] != ckSink {
+		t.Fatalf("plans must differ per mapper: snake=%v default=%v", p1.kinds, p2.kinds)
+	}
+}
+
+// --------------------------------
+// Prefixed flattening
+// --------------------------------
+
+// TestScanAll_JoinWithPrefixes verifies that a JOIN aliased with u_/o_ columns maps into
+// nested structs via `db:",prefix=..."` without retagging the nested types.
+func TestScanAll_JoinWithPrefixes(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type User struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	type Order struct {
+		ID    int     `db:"id"`
+		Total float64 `db:"total"`
+	}
+	type Row struct {
+		User  User   `db:",prefix=u_"`
+		Order *Order `db:",prefix=o_"`
+	}
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"u_id", "u_name", "o_id", "o_total"}).
+			AddRow(1, "alice", 10, 9.5).
+			AddRow(2, "bob", 11, 1.0))
+
+	var out []Row
+	assertNoError(t, New(Postgres).Write("SELECT ...").ScanAll(db, &out))
+	if len(out) != 2 || out[0].User.Name != "alice" || out[0].Order.ID != 10 || out[1].Order.Total != 1.0 {
+		t.Fatalf("got %+v", out)
+	}
+}
+
+// --------------------------------
+// Strict scans
+// --------------------------------
+
+// TestStrictColumns_ErrorsOnUnmappedColumns verifies that StrictColumns rejects result
+// columns without a destination field and names them in the error.
+func TestStrictColumns_ErrorsOnUnmappedColumns(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		ID int `db:"id"`
+	}
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "fullname", "extra"}).AddRow(1, "a", "b"))
+
+	s := New(Postgres, Config{StrictColumns: true})
+	var out []Row
+	err := s.Write("SELECT id, fullname, extra FROM t").ScanAll(db, &out)
+	if !errors.Is(err, ErrColumnUnmapped) {
+		t.Fatalf("expected ErrColumnUnmapped, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"fullname", "extra"`) {
+		t.Fatalf("error should name the columns: %v", err)
+	}
+}
+
+// TestStrictFields_ErrorsOnUnfilledTaggedFields verifies that StrictFields rejects tagged
+// fields (including flattened ones) that receive no column, while untagged fields are exempt.
+func TestStrictFields_ErrorsOnUnfilledTaggedFields(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Audit struct {
+		CreatedAt string `db:"created_at"`
+	}
+	type Row struct {
+		ID    int    `db:"id"`
+		Name  string `db:"name"`
+		Note  string // untagged: never required
+		Audit Audit
+	}
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	s := New(Postgres, Config{StrictFields: true})
+	var r Row
+	err := s.Write("SELECT id FROM t").ScanOne(db, &r)
+	if !errors.Is(err, ErrFieldUnfilled) {
+		t.Fatalf("expected ErrFieldUnfilled, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "CreatedAt (created_at), Name (name)") || strings.Contains(err.Error(), "Note") {
+		t.Fatalf("error should name exactly the tagged fields: %v", err)
+	}
+}
+
+// TestStrict_CheckedOncePerPlan verifies that the strict checks run when a
+// plan is built and that later scans with the same columns reuse the outcome.
+func TestStrict_CheckedOncePerPlan(t *testing.T) {
+	type Row struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	s := New(Postgres, Config{StrictColumns: true, StrictFields: true})
+	for _, tc := range []struct {
+		cols []string
+		want error
+	}{
+		{[]string{"id", "extra"}, ErrColumnUnmapped},
+		{[]string{"id"}, ErrFieldUnfilled},
+	} {
+		_, err1 := s.mapper.getScanPlan(tc.cols, reflect.TypeOf(Row{}))
+		_, err2 := s.mapper.getScanPlan(tc.cols, reflect.TypeOf(&Row{}))
+		if !errors.Is(err1, tc.want) || err1 != err2 {
+			t.Fatalf("%v: want the same cached %v, got %v and %v", tc.cols, tc.want, err1, err2)
+		}
+	}
+	p, err := s.mapper.getScanPlan([]string{"id", "name"}, reflect.TypeOf(Row{}))
+	assertNoError(t, err)
+	if p.unmappedErr != nil || p.unfilledErr != nil {
+		t.Fatalf("unexpected strict errors: %v, %v", p.unmappedErr, p.unfilledErr)
+	}
+}
+
+// TestStrict_AllMapped_Succeeds ensures strict modes do not affect fully mapped scans,
+// scalar destinations, or the default (non-strict) SQLR.
+func TestStrict_AllMapped_Succeeds(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Row struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	s := New(MySQL, Config{StrictColumns: true, StrictFields: true})
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	var r Row
+	assertNoError(t, s.Write("SELECT id, name FROM t").ScanOne(db, &r))
+	if r.ID != 1 || r.Name != "a" {
+		t.Fatalf("got %+v", r)
+	}
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	var n int
+	assertNoError(t, s.Write("SELECT COUNT(*) FROM t").ScanOne(db, &n))
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "other"}).AddRow(1, "x"))
+	var lax Row
+	assertNoError(t, New(MySQL).Write("SELECT id, other FROM t").ScanOne(db, &lax))
+}
+
+// --------------------------------
+// Map scans
+// --------------------------------
+
+// TestScanOne_IntoMap verifies ScanOne into map[string]any keyed by column names.
+func TestScanOne_IntoMap(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "note"}).AddRow(int64(1), "alice", nil))
+
+	var m map[string]any
+	assertNoError(t, New(Postgres).Write("SELECT id, name, note FROM t").ScanOne(db, &m))
+	want := map[string]any{"id": int64(1), "name": "alice", "note": nil}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("got %#v, want %#v", m, want)
+	}
+}
+
+// TestScanAll_IntoMaps verifies ScanAll into []map[string]any allocates one map per row,
+// and that One/All/Rows accept map rows too.
+func TestScanAll_IntoMaps(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	s := New(MySQL)
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v"}).AddRow(1, "a").AddRow(2, "b"))
+	var out []map[string]any
+	assertNoError(t, s.Write("SELECT id, v FROM t").ScanAll(db, &out))
+	if len(out) != 2 || out[0]["v"] != "a" || out[1]["id"] != int64(2) {
+		t.Fatalf("got %#v", out)
+	}
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	var seen []map[string]any
+	for m, err := range Rows[map[string]any](context.Background(), db, s.Write("SELECT id FROM t")) {
+		assertNoError(t, err)
+		seen = append(seen, m)
+	}
+	if len(seen) != 2 || seen[0]["id"] != int64(1) || seen[1]["id"] != int64(2) {
+		t.Fatalf("rows must not share maps: %#v", seen)
+	}
+}
+
+// TestScanMap_NamedKeyAndMapTypes verifies scans into maps whose key or map
+// type is named, such as map[Col]any and type Record map[string]any.
+func TestScanMap_NamedKeyAndMapTypes(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	type Col string
+	type Record map[string]any
+	s := New(Postgres)
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "note"}).AddRow(int64(1), nil))
+	var m map[Col]any
+	assertNoError(t, s.Write("SELECT id, note FROM t").ScanOne(db, &m))
+	if want := (map[Col]any{"id": int64(1), "note": nil}); !reflect.DeepEqual(m, want) {
+		t.Fatalf("got %#v, want %#v", m, want)
+	}
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)))
+	var keyed []map[Col]any
+	assertNoError(t, s.Write("SELECT id FROM t").ScanAll(db, &keyed))
+	if len(keyed) != 2 || keyed[1]["id"] != int64(2) {
+		t.Fatalf("got %#v", keyed)
+	}
+
+	mock.ExpectQuery(".*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(3)))
+	var r Record
+	assertNoError(t, s.Write("SELECT id FROM t").ScanOne(db, &r))
+	if r["id"] != int64(3) {
+		t.Fatalf("got %#v", r)
+	}
+}
+
+// TestScanMap_BytesToString verifies that MapBytesToString converts []byte values of
+// textual columns while binary columns (by database type name) stay []byte.
+func TestScanMap_BytesToString(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+
+	newRows := func() *sqlmock.Rows {
+		return mock.NewRowsWithColumnDefinition(
+			mock.NewColumn("name").OfType("VARCHAR", ""),
+			mock.NewColumn("data").OfType("BYTEA", nil),
+		).AddRow([]byte("alice"), []byte{0x01, 0x02})
+	}
+
+	mock.ExpectQuery(".*").WillReturnRows(newRows())
+	m, err := One[map[string]any](context.Background(), db,
+		New(Postgres, Config{MapBytesToString: true}).Write("SELECT name, data FROM t"))
+	assertNoError(t, err)
+	if m["name"] != "alice" {
+		t.Fatalf("name: got %#v, want string", m["name"])
+	}
+	if b, ok := m["data"].([]byte); !ok || len(b) != 2 {
+		t.Fatalf("data: got %#v, want []byte", m["data"])
+	}
+
+	// Without the option, driver bytes are kept as-is.
+	mock.ExpectQuery(".*").WillReturnRows(newRows())
+	m, err = One[map[string]any](context.Background(), db, New(Postgres).Write("SELECT name, data FROM t"))
+	assertNoError(t, err)
+	if _, ok := m["name"].([]byte); !ok {
+		t.Fatalf("name: got %#v, want []byte", m["name"])
+	}
+}
+
 // rowsLike minimal adapter (no driver/mock allocs) for benchmarks
 type rowsLike struct {
 	cols []string
@@ -1695,7 +2313,7 @@ func scanAllRowsLike(rows *rowsLike, dest any) error {
 			structT = elemT
 		}
 
-		plan, err := getScanPlan(cols, structT)
+		plan, err := defaultMapper.getScanPlan(cols, structT)
 		if err != nil {
 			return err
 		}
@@ -1831,7 +2449,7 @@ func scanOneStructRowsLike(rows *rowsLike, dest any) error {
 	}
 
 	cols := rows.cols
-	fmap := fieldIndexMap(rv.Type()) // column name -> fieldInfo (with path []int)
+	fmap := defaultMapper.fieldIndexMap(rv.Type()) // column name -> fieldInfo (with path []int)
 
 	targets := make([]any, len(cols))
 	sinks := make([]any, len(cols))
@@ -1,9 +1,11 @@
 package sqlr
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,192 +16,327 @@ type scalar struct {
 	v any
 }
 
+// emptyAs is a wrapper overriding the empty-slice policy of a value.
+type emptyAs struct {
+	v      any
+	policy EmptyPolicy
+}
+
 // ambiguousSentinel is used to bubble up an "ambiguous field" condition
 // through singleLookup without changing call signatures.
 type ambiguousSentinel struct {
 	name string
 }
 
+// tokenKind classifies a lexical token produced by tokenize.
+type tokenKind uint8
+
+const (
+	tkText       tokenKind = iota // literal SQL (including quoted text and comments)
+	tkParam                       // :name
+	tkRows                        // :name{a,b,...}
+	tkIdent                       // :!name (quoted identifier)
+	tkSection                     // /*? opening an optional section
+	tkSectionEnd                  // */ closing an optional section
+	tkQuoted                      // quoted literal or identifier (split lexers only)
+	tkComment                     // comment (split lexers only)
+)
+
+// token is a single unit of a tokenized SQL statement. Literal tokens carry
+// their text; placeholder tokens carry the name and, for rows-blocks, the
+// column list; section openers carry the names of the :name and :!name
+// placeholders inside in cols, and those of the rows-blocks in rows. pos is
+// the byte offset of the token in the source SQL.
+type token struct {
+	kind tokenKind
+	text string
+	name string
+	cols []string
+	rows []string
+	pos  int
+}
+
+// Lexical states of the tokenizer.
+const (
+	sText = iota
+	sSQ   // '...'
+	sDQ   // "..."
+	sBT   // `...` (MySQL/SQLite)
+	sBR   // [...] (SQL Server)
+	sLC   // line comment -- or # (MySQL only)
+	sBC   // block comment /* ... */
+	sDQD  // $tag$ ... $tag$ (dollar-quoted)
+	sQQ   // q'[ ... ]' (Oracle alternative quoting)
+)
+
+// stateNames describes the lexical states for error messages.
+var stateNames = [...]string{
+	sText: "text",
+	sSQ:   "single-quoted literal",
+	sDQ:   "double-quoted identifier",
+	sBT:   "backtick-quoted identifier",
+	sBR:   "bracket-quoted identifier",
+	sLC:   "line comment",
+	sBC:   "block comment",
+	sDQD:  "dollar-quoted block",
+	sQQ:   "q-quoted literal",
+}
+
 var structIndexCache = newFieldCache(cacheSize)
 
 // parse performs the SQL building and parameter binding. It walks the input
 // SQL, substitutes :name placeholders (including rows blocks :name{a,b}),
 // tracks placeholder counting, and emits dialect-specific placeholders.
-func parse(dialect Dialect, q string, inputs []any, config Config) (string, []any, error) {
-	// Build fallback resolvers and detect fast bag (map[string]any) materialized in Bind.
-	fastBag := parseFastBag(inputs)
-	lookupFB, rowsLookupFB, err := makeMultiResolver(inputs)
-	if err != nil {
-		return "", nil, err
-	}
-	lookup := parseMakeValueLookup(fastBag, lookupFB)
-	rowsLookup := parseMakeRowsLookup(fastBag, rowsLookupFB)
-
+// Tokens are emitted as soon as they are recognized, so the SQL is scanned
+// exactly once.
+func parse(s *SQLR, q string, inputs []any) (string, []any, error) {
 	// Rough placeholder estimate to pre-size buffers.
 	est := strings.Count(q, ":") - strings.Count(q, "::")
 	if est < 0 {
 		est = 0
 	}
-	args := make([]any, 0, est)
+	var e emitter
+	e.init(s, inputs, len(q), est)
+	lx := lexer{q: q, syn: s.syntax, config: s.config}
+	for {
+		tok, ok, err := lx.next()
+		if err != nil {
+			return "", nil, err
+		}
+		if !ok {
+			break
+		}
+		if err := e.emit(tok); err != nil {
+			return "", nil, newBuildError(q, tok.pos, err)
+		}
+	}
+	return e.buf.String(), e.args, nil
+}
 
-	var buf strings.Builder
-	extraPer := 1
-	switch dialect {
-	case Postgres, SQLServer:
-		extraPer = 4
+// lexer walks a SQL string with the lexical state machine and yields literal
+// segments and placeholders in source order. Quoted literals, quoted
+// identifiers, comments and dollar-quoted blocks are yielded as literal text,
+// or as tkQuoted and tkComment tokens of their own when split is set.
+type lexer struct {
+	q       string
+	syn     Syntax
+	config  Config
+	i       int    // cursor
+	start   int    // start of the pending literal segment
+	state   int    // current lexical state
+	dqTag   string // active $tag$ for PG-like dollar-quoting
+	pending token  // placeholder found after a literal segment
+	hasPend bool
+	open    int       // offset where the current quoted/comment state began
+	section bool      // inside a /*? ... */ optional section
+	strict  bool      // report quotes and block comments left open at the end
+	split   bool      // yield quotes and comments apart from the literal text
+	special tokenKind // split: kind of the quote or comment from open, or tkText
+}
+
+// next returns the next token. ok is false once the input is exhausted.
+func (lx *lexer) next() (tok token, ok bool, err error) {
+	if lx.hasPend {
+		lx.hasPend = false
+		return lx.pending, true, nil
 	}
-	buf.Grow(len(q) + 16 + est*extraPer)
-
-	// Parser state
-	const (
-		sText = iota
-		sSQ   // '...'
-		sDQ   // "..."
-		sBT   // `...` (MySQL/SQLite)
-		sBR   // [...] (SQL Server)
-		sLC   // line comment -- or # (MySQL only)
-		sBC   // block comment /* ... */
-		sDQD  // $tag$ ... $tag$ (dollar-quoted)
-	)
-	state := sText
-	var dqTag string // active $tag$ for PG-like dollar-quoting
-	n := 0
 
-	for i := 0; i < len(q); {
+	q := lx.q
+	for i := lx.i; i < len(q); {
 		c := q[i]
 
-		switch state {
+		switch lx.state {
 		case sText:
-			// 1) Try entering a quoted/comment state (writes the opener to buf if any)
-			if newState, newI, newTag, ok := parseTryEnterSpecial(q, i, dialect, &buf); ok {
-				state, i, dqTag = newState, newI, newTag
+			if lx.special != tkText {
+				return lx.yieldSpecial(i)
+			}
+			// 0) Optional sections: /*? ... */
+			if lx.section && c == '*' && i+1 < len(q) && q[i+1] == '/' {
+				lx.section = false
+				return lx.yield(token{kind: tkSectionEnd, pos: i}, i, i+2)
+			}
+			if c == '/' && strings.HasPrefix(q[i:], "/*?") {
+				if lx.section {
+					return lx.fail(i, fmt.Errorf("%w: nested /*?", ErrSectionMalformed))
+				}
+				names, rows, err := lx.sectionParams(i + 3)
+				if err != nil {
+					return lx.fail(i, err)
+				}
+				lx.section = true
+				return lx.yield(token{kind: tkSection, cols: names, rows: rows, pos: i}, i, i+3)
+			}
+			// 1) Try entering a quoted/comment state
+			if newState, newI, newTag, ok := parseTryEnterSpecial(q, i, lx.syn); ok {
+				lx.state, lx.open, i, lx.dqTag = newState, i, newI, newTag
+				if lx.split {
+					lx.special = tkQuoted
+					if newState == sLC || newState == sBC {
+						lx.special = tkComment
+					}
+				}
 				continue
 			}
 			// 2) Try a :name or :name{...} placeholder
 			if parseIsParamStart(q, i) {
-				newI, handled, err := parseHandlePlaceholder(q, i, dialect, config, lookup, rowsLookup, &buf, &args, &n)
+				ph, newI, handled, err := parseReadPlaceholder(q, i, lx.config)
 				if err != nil {
-					return "", nil, err
+					return lx.fail(i, err)
 				}
 				if handled {
-					i = newI
-					continue
+					return lx.yield(ph, i, newI)
 				}
 			}
 			// 3) Plain text byte
-			buf.WriteByte(c)
 			i++
 
 		case sSQ:
 			// single-quoted literal with backslash and doubled-quote handling
 			if c == '\\' {
-				buf.WriteByte(c)
-				i++
-				if i < len(q) {
-					buf.WriteByte(q[i])
-					i++
-				}
+				i += 2
 				continue
 			}
-			buf.WriteByte(c)
 			i++
 			if c == '\'' {
 				if i < len(q) && q[i] == '\'' {
-					buf.WriteByte(q[i])
 					i++
 				} else {
-					state = sText
+					lx.state = sText
 				}
 			}
 
 		case sDQ:
 			// double-quoted literal with backslash and doubled-quote handling
 			if c == '\\' {
-				buf.WriteByte(c)
-				i++
-				if i < len(q) {
-					buf.WriteByte(q[i])
-					i++
-				}
+				i += 2
 				continue
 			}
-			buf.WriteByte(c)
 			i++
 			if c == '"' {
 				if i < len(q) && q[i] == '"' {
-					buf.WriteByte(q[i])
 					i++
 				} else {
-					state = sText
+					lx.state = sText
 				}
 			}
 
 		case sBT:
 			// backtick-quoted identifier (MySQL/SQLite)
-			buf.WriteByte(c)
 			i++
 			if c == '`' {
 				if i < len(q) && q[i] == '`' {
-					buf.WriteByte(q[i])
 					i++
 				} else {
-					state = sText
+					lx.state = sText
 				}
 			}
 
 		case sBR:
 			// bracket-quoted identifier (SQL Server)
-			buf.WriteByte(c)
 			i++
 			if c == ']' {
 				if i < len(q) && q[i] == ']' {
-					buf.WriteByte(q[i])
 					i++
 				} else {
-					state = sText
+					lx.state = sText
 				}
 			}
 
 		case sLC:
 			// line comment: -- ... or # ... (MySQL)
-			buf.WriteByte(c)
 			i++
 			if c == '\n' || c == '\r' {
-				state = sText
+				lx.state = sText
 			}
 
 		case sBC:
 			// block comment: /* ... */
-			buf.WriteByte(c)
 			i++
 			if c == '*' && i < len(q) && q[i] == '/' {
-				buf.WriteByte('/')
 				i++
-				state = sText
+				lx.state = sText
 			}
 
-		case sDQD:
-			// dollar-quoted block: $tag$ ... $tag$
-			if dqTag == "" {
-				buf.WriteString(q[i:])
-				i = len(q)
-				break
+		case sDQD, sQQ:
+			// dollar-quoted block: $tag$ ... $tag$, or Oracle q'[ ... ]'
+			// (the tag is the closing delimiter followed by a quote)
+			p := -1
+			if lx.dqTag != "" {
+				p = strings.Index(q[i:], lx.dqTag)
 			}
-			p := strings.Index(q[i:], dqTag)
 			if p < 0 {
-				buf.WriteString(q[i:])
 				i = len(q)
 			} else {
-				buf.WriteString(q[i : i+p])
-				buf.WriteString(dqTag)
-				i += p + len(dqTag)
-				dqTag = ""
-				state = sText
+				i += p + len(lx.dqTag)
+				lx.dqTag = ""
+				lx.state = sText
 			}
 		}
 	}
 
-	return buf.String(), args, nil
+	// Flush the trailing literal segment.
+	if lx.strict && lx.state != sText && lx.state != sLC {
+		return lx.fail(lx.open, fmt.Errorf("%w: %s", ErrUnterminated, stateNames[lx.state]))
+	}
+	if lx.special != tkText {
+		return lx.yieldSpecial(len(q))
+	}
+	lx.i = len(q)
+	if lx.start < len(q) {
+		start := lx.start
+		lx.start = len(q)
+		return token{kind: tkText, text: q[start:], pos: start}, true, nil
+	}
+	return token{}, false, nil
+}
+
+// yield returns tok, found at q[i:newI], preceded by the pending literal
+// segment if there is one, and moves the cursor past it.
+func (lx *lexer) yield(tok token, i, newI int) (token, bool, error) {
+	start := lx.start
+	lx.i, lx.start = newI, newI
+	if start < i {
+		lx.pending, lx.hasPend = tok, true
+		return token{kind: tkText, text: lx.q[start:i], pos: start}, true, nil
+	}
+	return tok, true, nil
+}
+
+// yieldSpecial returns the quote or comment of a split lexer that started at
+// lx.open and ends at i, preceded by the pending literal segment.
+func (lx *lexer) yieldSpecial(i int) (token, bool, error) {
+	kind := lx.special
+	lx.special = tkText
+	return lx.yield(token{kind: kind, text: lx.q[lx.open:i], pos: lx.open}, lx.open, i)
+}
+
+// fail reports err as a *BuildError at offset pos of the SQL.
+func (lx *lexer) fail(pos int, err error) (token, bool, error) {
+	return token{}, false, newBuildError(lx.q, pos, err)
+}
+
+// sectionParams scans the optional section whose body starts at from and
+// returns the names of the placeholders it contains, rows-blocks apart.
+func (lx *lexer) sectionParams(from int) (names, rows []string, err error) {
+	sub := lexer{q: lx.q, syn: lx.syn, config: lx.config, i: from, start: from, section: true}
+	for {
+		tok, ok, err := sub.next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			break
+		}
+		switch tok.kind {
+		case tkSectionEnd:
+			return names, rows, nil
+		case tkParam, tkIdent:
+			names = append(names, tok.name)
+		case tkRows:
+			rows = append(rows, tok.name)
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: unclosed /*?", ErrSectionMalformed)
 }
 
 // parseFastBag returns the last input if it is a map[string]any, otherwise nil.
@@ -213,92 +350,61 @@ func parseFastBag(inputs []any) map[string]any {
 	return nil
 }
 
-// parseMakeValueLookup returns a composite lookup that checks the fast bag first,
-// then falls back to the generic resolver.
-func parseMakeValueLookup(fastBag map[string]any, fallback func(string) (any, bool)) func(string) (any, bool) {
-	return func(name string) (any, bool) {
-		if fastBag != nil {
-			if v, ok := fastBag[name]; ok {
-				return v, true
-			}
-		}
-		return fallback(name)
-	}
-}
-
-// parseMakeRowsLookup returns a composite rows-lookup that checks the fast bag first,
-// then falls back to the generic rows resolver.
-func parseMakeRowsLookup(
-	fastBag map[string]any,
-	fallback func(string) ([]rowVal, bool),
-) func(string) ([]rowVal, bool) {
-	return func(name string) ([]rowVal, bool) {
-		if fastBag != nil {
-			if v, ok := fastBag[name]; ok {
-				if rows, ok := rowsFromSliceValue(reflect.ValueOf(v)); ok {
-					return rows, true
-				}
-			}
-		}
-		return fallback(name)
-	}
-}
-
 // parseIsParamStart checks if q[i] starts a :name placeholder (not a :: cast).
 func parseIsParamStart(q string, i int) bool {
 	return q[i] == ':' && (i+1) < len(q) && q[i+1] != ':' && !(i > 0 && q[i-1] == ':')
 }
 
-// parseTryEnterSpecial inspects q[i] for comment/string/identifier openers,
-// writes them to buf and returns the new state and cursor when matched.
-func parseTryEnterSpecial(q string, i int, dialect Dialect, buf *strings.Builder) (newState int, newI int, dqTag string, ok bool) {
+// parseTryEnterSpecial inspects q[i] for comment/string/identifier openers
+// and returns the new state and the cursor just after the opener when matched.
+func parseTryEnterSpecial(q string, i int, syn Syntax) (newState int, newI int, dqTag string, ok bool) {
 	c := q[i]
 
 	// line comment: -- or # (MySQL)
 	if c == '-' && i+1 < len(q) && q[i+1] == '-' {
-		buf.WriteString("--")
-		return 5 /* sLC */, i + 2, "", true
+		return sLC, i + 2, "", true
 	}
-	if c == '#' && dialect == MySQL {
-		buf.WriteByte('#')
-		return 5 /* sLC */, i + 1, "", true
+	if c == '#' && syn.HashComments {
+		return sLC, i + 1, "", true
 	}
 
 	// block comment: /*
 	if c == '/' && i+1 < len(q) && q[i+1] == '*' {
-		buf.WriteString("/*")
-		return 6 /* sBC */, i + 2, "", true
+		return sBC, i + 2, "", true
 	}
 
 	// single-quoted literal
 	if c == '\'' {
-		buf.WriteByte(c)
-		return 1 /* sSQ */, i + 1, "", true
+		return sSQ, i + 1, "", true
 	}
 
 	// double-quoted literal
 	if c == '"' {
-		buf.WriteByte(c)
-		return 2 /* sDQ */, i + 1, "", true
+		return sDQ, i + 1, "", true
 	}
 
 	// backtick-quoted identifier (MySQL/SQLite)
-	if c == '`' && (dialect == MySQL || dialect == SQLite) {
-		buf.WriteByte(c)
-		return 3 /* sBT */, i + 1, "", true
+	if c == '`' && syn.Backticks {
+		return sBT, i + 1, "", true
 	}
 
 	// bracket-quoted identifier (SQL Server)
-	if c == '[' && dialect == SQLServer {
-		buf.WriteByte(c)
-		return 4 /* sBR */, i + 1, "", true
+	if c == '[' && syn.Brackets {
+		return sBR, i + 1, "", true
+	}
+
+	// Oracle alternative quoting: q'[...]', q'{...}', q'<...>', q'(...)',
+	// q'!...!' (also Q'...' and the national nq'...')
+	if (c == 'q' || c == 'Q') && syn.QQuotes {
+		if closing, ok := readOracleQuote(q, i); ok {
+			return sQQ, i + 3, closing, true
+		}
 	}
 
 	// dollar-quoted: $tag$
-	if c == '$' {
+	if c == '$' && syn.DollarQuotes {
 		if tag, ok := readDollarTag(q[i:]); ok {
-			buf.WriteString(tag)
-			return 7 /* sDQD */, i + len(tag), tag, true
+			return sDQD, i + len(tag), tag, true
 		}
 	}
 
@@ -318,175 +424,437 @@ func parseReadName(q string, j int) (name string, k int, ok bool) {
 	return q[j:k], k, true
 }
 
-// parseHandlePlaceholder handles :name and :name{...} from q[i] (where q[i]==':').
-// On success, it advances the cursor and emits into buf/args adjusting n.
-func parseHandlePlaceholder(
-	q string,
-	i int,
-	dialect Dialect,
-	config Config,
-	lookup func(string) (any, bool),
-	rowsLookup func(string) ([]rowVal, bool),
-	buf *strings.Builder,
-	args *[]any,
-	n *int,
-) (newI int, handled bool, err error) {
+// parseReadPlaceholder reads :name, :!name or :name{...} from q[i] (where q[i]==':').
+// On success, it returns the placeholder token and the cursor just after it.
+// handled is false when the colon does not start a placeholder.
+func parseReadPlaceholder(q string, i int, config Config) (tok token, newI int, handled bool, err error) {
 	j := i + 1
+	ident := j < len(q) && q[j] == '!'
+	if ident {
+		j++
+	}
 	if j >= len(q) {
-		return i, false, nil
+		return token{}, i, false, nil
 	}
 
 	name, k, ok := parseReadName(q, j)
 	if !ok {
-		return i, false, nil
+		return token{}, i, false, nil
 	}
 
 	// Enforce MaxNameLen
 	if config.MaxNameLen > 0 && len(name) > config.MaxNameLen {
-		return 0, false, fmt.Errorf("%w: %q (%d > %d)", ErrParamNameTooLong, name, len(name), config.MaxNameLen)
+		return token{}, 0, false, fmt.Errorf("%w: %q (%d > %d)", ErrParamNameTooLong, name, len(name), config.MaxNameLen)
+	}
+
+	// :!name identifier
+	if ident {
+		return token{kind: tkIdent, name: name, pos: i}, k, true, nil
 	}
 
 	// :name{...} rows-block
 	if k < len(q) && q[k] == '{' {
 		k2, cols, ok := readCols(q, k)
 		if !ok {
-			return 0, true, fmt.Errorf("%w: :%s{...}", ErrRowsMalformed, name)
+			return token{}, 0, true, fmt.Errorf("%w: :%s{...}", ErrRowsMalformed, name)
 		}
 		if len(cols) == 0 {
-			return 0, true, fmt.Errorf("%w: :%s{...} without columns", ErrRowsMalformed, name)
+			return token{}, 0, true, fmt.Errorf("%w: :%s{...} without columns", ErrRowsMalformed, name)
+		}
+		return token{kind: tkRows, name: name, cols: cols, pos: i}, k2, true, nil
+	}
+
+	// Simple :name (single value or slice expansion)
+	return token{kind: tkParam, name: name, pos: i}, k, true, nil
+}
+
+// emitter accumulates the rendered SQL and the bound args while tokens are
+// emitted. It resolves placeholder values against the Bind() inputs.
+type emitter struct {
+	spec    DialectSpec
+	config  Config
+	mapper  *fieldMapper
+	inputs  []any
+	fastBag map[string]any // bag materialized by Bind(k, v), checked first
+	buf     strings.Builder
+	args    []any
+	n       int
+	rows    []rowVal            // if set, overrides the rows of a :name{...} block
+	reuse   bool                // repeated scalar :name reuse their first ordinal
+	named   bool                // @name placeholders with sql.Named args
+	dual    bool                // EmptyFalse subqueries select FROM dual
+	seen    map[string]string   // reuse/named: name → rendered placeholder(s)
+	owners  map[string]string   // named: arg name → :name that bound it
+	allow   map[string]struct{} // Config.AllowedIdents, nil if unrestricted
+	skip    bool                // inside an optional section being dropped
+}
+
+// init prepares the emitter for the given inputs, pre-sizing buffers from
+// the SQL length and the estimated number of placeholders.
+func (e *emitter) init(s *SQLR, inputs []any, sqlLen, est int) {
+	e.spec = s.spec
+	e.allow = s.allowIdents
+	e.config = s.config
+	e.mapper = s.mapper
+	e.inputs = inputs
+	e.fastBag = parseFastBag(inputs)
+	e.args = make([]any, 0, est)
+	e.named = e.config.NamedArgs && s.syntax.NamedParams
+	e.reuse = e.named || e.config.ReuseParams && s.syntax.Numbered
+	e.dual = s.syntax.FromDual
+
+	extraPer := 1
+	if s.syntax.Numbered || s.syntax.NamedParams {
+		extraPer = 4
+	}
+	e.buf.Grow(sqlLen + 16 + est*extraPer)
+}
+
+// lookup resolves a single value for :name. It checks the fast bag first,
+// then the inputs with "last one wins" resolution: later Bind() inputs
+// override earlier ones.
+func (e *emitter) lookup(name string) (any, bool) {
+	if e.fastBag != nil {
+		if v, ok := e.fastBag[name]; ok {
+			return v, true
+		}
+	}
+	for i := len(e.inputs) - 1; i >= 0; i-- {
+		if v, ok := e.mapper.singleLookup(e.inputs[i], name); ok {
+			return v, true
 		}
+	}
+	return nil, false
+}
+
+// rowsLookup resolves the rows for a :name{...} block, with the same
+// precedence as lookup.
+func (e *emitter) rowsLookup(name string) ([]rowVal, bool) {
+	if e.fastBag != nil {
+		if v, ok := e.fastBag[name]; ok {
+			if rows, ok := rowsFromSliceValue(reflect.ValueOf(v)); ok {
+				return rows, true
+			}
+		}
+	}
+	for i := len(e.inputs) - 1; i >= 0; i-- {
+		if rows, ok := singleRowsLookup(e.inputs[i], name); ok {
+			return rows, true
+		}
+	}
+	return nil, false
+}
+
+// emit writes a single token: literal text is copied as-is, placeholders are
+// resolved and rendered as dialect-specific placeholders.
+func (e *emitter) emit(tok token) error {
+	if e.skip && tok.kind != tkSectionEnd {
+		return nil
+	}
+	switch tok.kind {
+	case tkSection:
+		e.skip = !e.sectionBound(tok.cols, tok.rows)
+		return nil
 
-		rows, ok := rowsLookup(name)
+	case tkSectionEnd:
+		e.skip = false
+		return nil
+
+	case tkText:
+		e.buf.WriteString(tok.text)
+		return nil
+
+	case tkRows:
+		rows, ok := e.rows, e.rows != nil
 		if !ok {
-			return 0, true, fmt.Errorf("%w: :%s{...}", ErrParamMissing, name)
+			rows, ok = e.rowsLookup(tok.name)
+		}
+		if !ok {
+			return fmt.Errorf("%w: :%s{...}", ErrParamMissing, tok.name)
 		}
 		if len(rows) == 0 {
-			return 0, true, fmt.Errorf("%w: :%s{...}", ErrRowsEmpty, name)
+			return fmt.Errorf("%w: :%s{...}", ErrRowsEmpty, tok.name)
 		}
+		if err := parseEnsureAdd(e.n, len(rows)*len(tok.cols), e.config); err != nil {
+			return err
+		}
+		return e.emitRowsBlock(tok.name, tok.cols, rows)
 
-		if err := parseEnsureAdd(*n, len(rows)*len(cols), config); err != nil {
-			return 0, true, err
+	case tkIdent:
+		v, ok := e.lookup(tok.name)
+		if !ok {
+			return fmt.Errorf("%w: :!%s", ErrParamMissing, tok.name)
+		}
+		if a, isAmbiguous := v.(ambiguousSentinel); isAmbiguous {
+			return fmt.Errorf("%w: %q", ErrFieldAmbiguous, a.name)
+		}
+		return e.emitIdent(tok.name, v)
+
+	default:
+		if e.reuse {
+			if ph, ok := e.seen[tok.name]; ok {
+				e.buf.WriteString(ph)
+				return nil
+			}
+		}
+		v, ok := e.lookup(tok.name)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrParamMissing, tok.name)
 		}
-		if err := parseEmitRowsBlock(name, cols, rows, dialect, buf, args, n); err != nil {
-			return 0, true, err
+		// Bubble up ambiguous from fallback path only
+		if a, isAmbiguous := v.(ambiguousSentinel); isAmbiguous {
+			return fmt.Errorf("%w: %q", ErrFieldAmbiguous, a.name)
 		}
-		return k2, true, nil
+		n, start := e.n, e.buf.Len()
+		if err := e.emitValue(tok.name, v); err != nil {
+			return err
+		}
+		// Later occurrences repeat the rendered placeholder(s) without binding
+		// again: always for named args, for single placeholders otherwise.
+		if e.reuse && (e.named || e.n == n+1) {
+			if e.seen == nil {
+				e.seen = make(map[string]string)
+			}
+			e.seen[tok.name] = e.buf.String()[start:]
+		}
+		return nil
 	}
+}
 
-	// Simple :name (single value or slice expansion)
-	v, ok := lookup(name)
-	if !ok {
-		return 0, true, fmt.Errorf("%w: %s", ErrParamMissing, name)
+// sectionBound reports whether every name and rows-block is bound, and with
+// Config.SkipZeroSections bound to a non-zero value or a non-empty list of
+// rows. Rows-blocks resolve as they are emitted, through rowsLookup.
+func (e *emitter) sectionBound(names, rows []string) bool {
+	for _, name := range names {
+		v, ok := e.lookup(name)
+		if !ok {
+			return false
+		}
+		if e.config.SkipZeroSections && isZeroValue(v) {
+			return false
+		}
+	}
+	for _, name := range rows {
+		rs, ok := e.rows, e.rows != nil
+		if !ok {
+			rs, ok = e.rowsLookup(name)
+		}
+		if !ok || e.config.SkipZeroSections && len(rs) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isZeroValue reports whether v is nil, the zero value of its type, or an
+// empty slice, array or map. Scalar and EmptyAs wrappers are looked through.
+func isZeroValue(v any) bool {
+	switch w := v.(type) {
+	case scalar:
+		v = w.v
+	case emptyAs:
+		v = w.v
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return true
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+// emitOne emits a single placeholder for :name bound to v.
+func (e *emitter) emitOne(name string, v any) error {
+	if err := parseEnsureAdd(e.n, 1, e.config); err != nil {
+		return err
+	}
+	return e.put(v, name, 0, "")
+}
+
+// put writes the next placeholder and appends its arg. In named mode the
+// placeholder is @name, suffixed with _idx (when idx > 0) and _col (when
+// col != ""), and the arg is wrapped in sql.Named. A suffixed name equal to
+// the name of another placeholder (:ids expanded to @ids_1 next to :ids_1)
+// fails with ErrNamedConflict, since drivers would bind a single value.
+func (e *emitter) put(v any, name string, idx int, col string) error {
+	e.n++
+	if !e.named {
+		writePlaceholder(&e.buf, e.spec, e.n)
+		e.args = append(e.args, v)
+		return nil
+	}
+	start := e.buf.Len()
+	e.buf.WriteByte('@')
+	e.buf.WriteString(name)
+	if idx > 0 {
+		e.buf.WriteByte('_')
+		var tmp [20]byte
+		e.buf.Write(strconv.AppendInt(tmp[:0], int64(idx), 10))
+	}
+	if col != "" {
+		e.buf.WriteByte('_')
+		e.buf.WriteString(col)
+	}
+	arg := e.buf.String()[start+1:]
+	if owner, ok := e.owners[arg]; ok && owner != name {
+		return fmt.Errorf("%w: @%s is bound by both :%s and :%s", ErrNamedConflict, arg, owner, name)
+	}
+	if e.owners == nil {
+		e.owners = make(map[string]string)
 	}
+	e.owners[arg] = name
+	e.args = append(e.args, sql.Named(arg, v))
+	return nil
+}
+
+// emitEmpty renders an empty slice bound to :name according to policy.
+func (e *emitter) emitEmpty(name string, policy EmptyPolicy) error {
+	switch policy {
+	case EmptyNull:
+		e.buf.WriteString("NULL")
+	case EmptyFalse:
+		if e.dual {
+			e.buf.WriteString("SELECT NULL FROM dual WHERE 1=0")
+		} else {
+			e.buf.WriteString("SELECT NULL WHERE 1=0")
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrSliceEmpty, name)
+	}
+	return nil
+}
+
+// emitIdent renders v as quoted identifiers instead of binding it: a string
+// or Ident, or a comma-separated list for []string and []Ident.
+func (e *emitter) emitIdent(name string, v any) error {
+	switch id := v.(type) {
+	case Ident:
+		return e.writeIdent(name, string(id))
+	case string:
+		return e.writeIdent(name, id)
+	case []Ident:
+		return emitIdentList(e, name, id)
+	case []string:
+		return emitIdentList(e, name, id)
+	}
+	return fmt.Errorf("%w: :!%s bound to %T", ErrIdentInvalid, name, v)
+}
 
-	// Bubble up ambiguous from fallback path only
-	if a, isAmbiguous := v.(ambiguousSentinel); isAmbiguous {
-		return 0, true, fmt.Errorf("%w: %q", ErrFieldAmbiguous, a.name)
+// emitIdentList renders ids as a comma-separated list of quoted identifiers.
+func emitIdentList[S ~string](e *emitter, name string, ids []S) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: %s", ErrSliceEmpty, name)
 	}
+	for i, id := range ids {
+		if i > 0 {
+			e.buf.WriteString(", ")
+		}
+		if err := e.writeIdent(name, string(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return parseEmitValue(name, v, dialect, config, buf, args, n, k)
+// writeIdent checks id against the allow-list and writes it quoted by the
+// dialect, part by part for dotted names (schema.table → "schema"."table").
+func (e *emitter) writeIdent(name, id string) error {
+	if e.allow != nil {
+		if _, ok := e.allow[id]; !ok {
+			return fmt.Errorf("%w: %q (:%s)", ErrIdentNotAllowed, id, name)
+		}
+	}
+	if !quoteIdent(&e.buf, e.spec, id) {
+		return fmt.Errorf("%w: %q (:%s)", ErrIdentInvalid, id, name)
+	}
+	return nil
 }
 
-// parseEmitValue emits either a single placeholder or a list (slice/array expansion).
-func parseEmitValue(
-	name string,
-	v any,
-	dialect Dialect,
-	config Config,
-	buf *strings.Builder,
-	args *[]any,
-	n *int,
-	k int,
-) (newI int, handled bool, err error) {
+// quoteIdent writes id quoted by spec, part by part for dotted names. It
+// reports false, having written nothing, when a part is empty ("", "a..b").
+func quoteIdent(b *strings.Builder, spec DialectSpec, id string) bool {
+	if id == "" || id[0] == '.' || id[len(id)-1] == '.' || strings.Contains(id, "..") {
+		return false
+	}
+	for i := 0; ; i++ {
+		part, rest, more := strings.Cut(id, ".")
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(spec.QuoteIdent(part))
+		if !more {
+			return true
+		}
+		id = rest
+	}
+}
+
+// emitValue emits either a single placeholder or a list (slice/array expansion).
+func (e *emitter) emitValue(name string, v any) error {
+	// Identifiers are rendered in place, never bound
+	switch v.(type) {
+	case Ident, []Ident:
+		return e.emitIdent(name, v)
+	}
+
+	empty := e.config.EmptySlices
+	if ea, ok := v.(emptyAs); ok {
+		v, empty = ea.v, ea.policy
+	}
+
 	// Single placeholder for scalar wrapper / driver.Valuer
 	if sc, ok := v.(scalar); ok {
-		if err := parseEnsureAdd(*n, 1, config); err != nil {
-			return 0, true, err
-		}
-		*n++
-		writePlaceholder(buf, dialect, *n)
-		*args = append(*args, sc.v)
-		return k, true, nil
+		return e.emitOne(name, sc.v)
 	}
 	if _, ok := v.(driver.Valuer); ok {
-		if err := parseEnsureAdd(*n, 1, config); err != nil {
-			return 0, true, err
-		}
-		*n++
-		writePlaceholder(buf, dialect, *n)
-		*args = append(*args, v)
-		return k, true, nil
+		return e.emitOne(name, v)
 	}
 
 	// []byte (or byte-slice-like) → single placeholder
 	if bs, ok := v.([]byte); ok {
-		if err := parseEnsureAdd(*n, 1, config); err != nil {
-			return 0, true, err
-		}
-		*n++
-		writePlaceholder(buf, dialect, *n)
-		*args = append(*args, bs)
-		return k, true, nil
+		return e.emitOne(name, bs)
 	}
 	rv := reflect.ValueOf(v)
 	if rv.IsValid() && rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
-		if err := parseEnsureAdd(*n, 1, config); err != nil {
-			return 0, true, err
-		}
-		*n++
-		writePlaceholder(buf, dialect, *n)
 		if rv.Type() != reflect.TypeOf([]byte(nil)) && rv.Type().ConvertibleTo(reflect.TypeOf([]byte(nil))) {
-			*args = append(*args, rv.Convert(reflect.TypeOf([]byte(nil))).Interface())
-		} else {
-			*args = append(*args, v)
+			return e.emitOne(name, rv.Convert(reflect.TypeOf([]byte(nil))).Interface())
 		}
-		return k, true, nil
+		return e.emitOne(name, v)
 	}
 
 	// Slice/array expansion (non-byte)
 	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
 		ln := rv.Len()
 		if ln == 0 {
-			return 0, true, fmt.Errorf("%w: %s", ErrSliceEmpty, name)
+			return e.emitEmpty(name, empty)
 		}
-		if err := parseEnsureAdd(*n, ln, config); err != nil {
-			return 0, true, err
+		if err := parseEnsureAdd(e.n, ln, e.config); err != nil {
+			return err
 		}
 
-		parseGrowArgs(args, ln)
-		parseGrowSQL(buf, ln)
+		parseGrowArgs(&e.args, ln)
+		parseGrowSQL(&e.buf, ln)
 
 		for t := 0; t < ln; t++ {
 			if t > 0 {
-				buf.WriteString(", ")
+				e.buf.WriteString(", ")
+			}
+			if err := e.put(rv.Index(t).Interface(), name, t+1, ""); err != nil {
+				return err
 			}
-			*n++
-			writePlaceholder(buf, dialect, *n)
-			*args = append(*args, rv.Index(t).Interface())
 		}
-		return k, true, nil
+		return nil
 	}
 
 	// Fallback: single placeholder
-	if err := parseEnsureAdd(*n, 1, config); err != nil {
-		return 0, true, err
-	}
-	*n++
-	writePlaceholder(buf, dialect, *n)
-	*args = append(*args, v)
-	return k, true, nil
+	return e.emitOne(name, v)
 }
 
-// parseEmitRowsBlock emits VALUES-like tuples for :name{col1,col2,...} using rows.
-func parseEmitRowsBlock(
-	name string,
-	cols []string,
-	rows []rowVal,
-	dialect Dialect,
-	buf *strings.Builder,
-	args *[]any,
-	n *int,
-) error {
+// emitRowsBlock emits VALUES-like tuples for :name{col1,col2,...} using rows.
+func (e *emitter) emitRowsBlock(name string, cols []string, rows []rowVal) error {
 	// Pre-compute fast-path structures (map keys and struct paths).
 	var (
 		colKeys       []reflect.Value
@@ -511,10 +879,10 @@ func parseEmitRowsBlock(
 	if rv0.IsValid() && rv0.Kind() == reflect.Struct {
 		colPathByType = make(map[reflect.Type][][]int, 4)
 		baseT := rv0.Type()
-		baseMap := fieldIndexMap(baseT)
+		baseMap := e.mapper.fieldIndexMap(baseT)
 		paths := make([][]int, len(cols))
 		for i, col := range cols {
-			fi, ok := baseMap[col]
+			fi, ok := baseMap[e.mapper.key(col)]
 			if !ok {
 				return fmt.Errorf("%w: %q in :%s{...} (record 0)", ErrColumnNotFound, col, name)
 			}
@@ -526,22 +894,35 @@ func parseEmitRowsBlock(
 		colPathByType[baseT] = paths
 	}
 
+	// Named args end with the column name, or with its position when the
+	// name cannot be part of a parameter name (a.b, "col").
+	argCols := cols
+	if e.named && slices.ContainsFunc(cols, func(c string) bool { return !isPlainIdent(c) }) {
+		argCols = make([]string, len(cols))
+		for i, col := range cols {
+			argCols[i] = col
+			if !isPlainIdent(col) {
+				argCols[i] = strconv.Itoa(i + 1)
+			}
+		}
+	}
+
 	need := len(rows) * len(cols)
-	parseGrowArgs(args, need)
-	parseGrowSQLRows(buf, len(cols), len(rows))
+	parseGrowArgs(&e.args, need)
+	parseGrowSQLRows(&e.buf, len(cols), len(rows))
 
 	for r := 0; r < len(rows); r++ {
 		if r > 0 {
-			buf.WriteString(", ")
+			e.buf.WriteString(", ")
 		}
-		buf.WriteByte('(')
+		e.buf.WriteByte('(')
 
 		rv := deIndirect(reflect.ValueOf(rows[r]))
 		useMapFast := (colKeys != nil && rv.IsValid() && rv.Kind() == reflect.Map && rv.Type().Key() == mapKeyT)
 
 		for cidx := range cols {
 			if cidx > 0 {
-				buf.WriteString(", ")
+				e.buf.WriteString(", ")
 			}
 
 			var v any
@@ -553,10 +934,10 @@ func parseEmitRowsBlock(
 					if colPathByType == nil {
 						colPathByType = make(map[reflect.Type][][]int, 4)
 					}
-					fm := fieldIndexMap(rv.Type())
+					fm := e.mapper.fieldIndexMap(rv.Type())
 					paths = make([][]int, len(cols))
 					for iCol, col := range cols {
-						fi, hit := fm[col]
+						fi, hit := fm[e.mapper.key(col)]
 						if !hit {
 							return fmt.Errorf("%w: %q in :%s{...} (record %d)", ErrColumnNotFound, col, name, r)
 						}
@@ -574,19 +955,19 @@ func parseEmitRowsBlock(
 					v, ok = mv.Interface(), true
 				}
 			} else {
-				v, ok = getColValue(rows[r], cols[cidx])
+				v, ok = e.mapper.getColValue(rows[r], cols[cidx])
 			}
 
 			if !ok {
 				return fmt.Errorf("%w: %q in :%s{...} (record %d)", ErrColumnNotFound, cols[cidx], name, r)
 			}
 
-			*n++
-			writePlaceholder(buf, dialect, *n)
-			*args = append(*args, v)
+			if err := e.put(v, name, r+1, argCols[cidx]); err != nil {
+				return err
+			}
 		}
 
-		buf.WriteByte(')')
+		e.buf.WriteByte(')')
 	}
 	return nil
 }
@@ -645,63 +1026,15 @@ func parseGrowSQLRows(buf *strings.Builder, numCols, numRows int) {
 	buf.Grow(extraSQL)
 }
 
-// writePlaceholder emits a dialect-specific placeholder token for argument idx.
-func writePlaceholder(b *strings.Builder, d Dialect, idx int) {
-	switch d {
-	case Postgres:
-		b.WriteByte('$')
-		var tmp [20]byte
-		n := strconv.AppendInt(tmp[:0], int64(idx), 10)
-		b.Write(n)
-	case SQLServer:
-		b.WriteString("@p")
-		var tmp [20]byte
-		n := strconv.AppendInt(tmp[:0], int64(idx), 10)
-		b.Write(n)
-	default: // MySQL, SQLite
-		b.WriteByte('?')
-	}
-}
-
 // --------------------------------
 // Resolver
 // --------------------------------
 
 type rowVal any
 
-// makeMultiResolver returns two resolvers:
-//  1. lookup(name) → single value for :name
-//  2. rowsLookup(name) → []rowVal for :name{...} blocks
-//
-// Resolution is "last one wins": later Bind() inputs override earlier ones.
-func makeMultiResolver(inputs []any) (
-	lookup func(string) (any, bool),
-	rowsLookup func(string) ([]rowVal, bool),
-	err error,
-) {
-	// Last-one-wins resolution: iterate inputs in reverse order
-	return func(name string) (any, bool) {
-			for i := len(inputs) - 1; i >= 0; i-- {
-				if v, ok := singleLookup(inputs[i], name); ok {
-					return v, true
-				}
-			}
-			return nil, false
-		},
-		func(name string) ([]rowVal, bool) {
-			for i := len(inputs) - 1; i >= 0; i-- {
-				if rows, ok := singleRowsLookup(inputs[i], name); ok {
-					return rows, true
-				}
-			}
-			return nil, false
-		},
-		nil
-}
-
 // singleLookup resolves a :name from a single Bind() input.
 // Supports map-like, struct-like (flattened), and pointers/interfaces thereof.
-func singleLookup(in any, name string) (any, bool) {
+func (fm *fieldMapper) singleLookup(in any, name string) (any, bool) {
 	v := reflect.ValueOf(in)
 	if !v.IsValid() {
 		return nil, false
@@ -734,8 +1067,8 @@ func singleLookup(in any, name string) (any, bool) {
 		}
 		return nil, false
 	case reflect.Struct:
-		m := fieldIndexMap(v.Type())
-		if fi, ok := m[name]; ok {
+		m := fm.fieldIndexMap(v.Type())
+		if fi, ok := m[fm.key(name)]; ok {
 			if fi.ambiguous {
 				// bubble sentinel; parse() will turn this into ErrFieldAmbiguous
 				return ambiguousSentinel{name: name}, true
@@ -752,7 +1085,7 @@ func singleLookup(in any, name string) (any, bool) {
 
 // getColValue extracts a value by column name from a row (struct/map, possibly wrapped).
 // It returns (value, true) on success or (nil, false) if the column is missing/unsupported.
-func getColValue(row any, col string) (any, bool) {
+func (fm *fieldMapper) getColValue(row any, col string) (any, bool) {
 	// FAST-PATH: map[string]any
 	if m, ok := row.(map[string]any); ok {
 		v, ok := m[col]
@@ -782,8 +1115,8 @@ func getColValue(row any, col string) (any, bool) {
 		}
 		return nil, false
 	case reflect.Struct:
-		m := fieldIndexMap(rv.Type())
-		if fi, ok := m[col]; ok {
+		m := fm.fieldIndexMap(rv.Type())
+		if fi, ok := m[fm.key(col)]; ok {
 			val, _ := getValueByPathAny(rv, fi.index)
 			return val, true
 		}
@@ -857,12 +1190,69 @@ func rowsFromSliceValue(v reflect.Value) ([]rowVal, bool) {
 	return nil, false
 }
 
+// fieldMapper resolves column and parameter names to struct fields.
+// Untagged fields are named through an optional NameMapper, and names can be
+// matched case-insensitively. Each mapper owns its caches, so SQLR instances
+// with different mapping rules never share field maps or scan plans; the
+// default mapper uses the package-level caches.
+type fieldMapper struct {
+	name          NameMapper
+	fold          bool        // match names case-insensitively
+	strictCols    bool        // error on result columns without a field
+	strictFields  bool        // error on tagged fields without a column
+	bytesToString bool        // map scans: []byte → string for non-binary columns
+	fields        *fieldCache // nil → structIndexCache
+	plans         *planCache  // nil → scanPlanCache
+}
+
+// defaultMapper matches names exactly against `db` tags or Go field names.
+var defaultMapper = &fieldMapper{}
+
+// newFieldMapper returns the mapper for the given configuration.
+func newFieldMapper(c Config) *fieldMapper {
+	if c.NameMapper == nil && !c.CaseInsensitive && !c.StrictColumns && !c.StrictFields &&
+		!c.MapBytesToString {
+		return defaultMapper
+	}
+	return &fieldMapper{
+		name:          c.NameMapper,
+		fold:          c.CaseInsensitive,
+		strictCols:    c.StrictColumns,
+		strictFields:  c.StrictFields,
+		bytesToString: c.MapBytesToString,
+		fields:        newFieldCache(cacheSize),
+		plans:         newPlanCache(cacheSize),
+	}
+}
+
+// key normalizes a column or parameter name for field map lookups.
+func (fm *fieldMapper) key(name string) string {
+	if fm.fold {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// fieldCache returns the field-index cache used by this mapper.
+func (fm *fieldMapper) fieldCache() *fieldCache {
+	if fm.fields != nil {
+		return fm.fields
+	}
+	return structIndexCache
+}
+
 // fieldIndexMap returns a mapping from column name → fieldInfo for the given type.
 // It flattens nested structs (excluding time.Time), honors `db:"name"` tags,
-// and supports `db:"name,scalar"` to force scalar binding.
+// supports `db:"name,scalar"` to force scalar binding, records the pk,
+// readonly and omitempty options (see crud.go), and `db:",prefix=u_"`
+// on a nested struct field to name its flattened children "u_<name>"
+// (prefixes of nested levels concatenate). Fields tagged `db:",many"` are
+// skipped (see aggregate.go). Untagged fields are
+// named by the mapper's NameMapper, and keys are normalized through key().
 // The result is cached in a two-tier cache.
-func fieldIndexMap(t reflect.Type) map[string]fieldInfo {
-	if m, ok := structIndexCache.get(t); ok {
+func (fm *fieldMapper) fieldIndexMap(t reflect.Type) map[string]fieldInfo {
+	cache := fm.fieldCache()
+	if m, ok := cache.get(t); ok {
 		return m
 	}
 
@@ -873,16 +1263,16 @@ func fieldIndexMap(t reflect.Type) map[string]fieldInfo {
 	}
 	if base.Kind() != reflect.Struct {
 		m := make(map[string]fieldInfo)
-		structIndexCache.put(t, m)
+		cache.put(t, m)
 		return m
 	}
 
 	m := make(map[string]fieldInfo, base.NumField())
 
 	visited := map[reflect.Type]bool{}
-	var walk func(rt reflect.Type, path []int)
+	var walk func(rt reflect.Type, path []int, prefix string)
 
-	walk = func(rt reflect.Type, path []int) {
+	walk = func(rt reflect.Type, path []int, prefix string) {
 		// Follow pointers for current type
 		for rt.Kind() == reflect.Pointer {
 			rt = rt.Elem()
@@ -906,19 +1296,43 @@ func fieldIndexMap(t reflect.Type) map[string]fieldInfo {
 				continue
 			}
 			name := f.Name
-			scalar := false
+			if fm.name != nil {
+				name = fm.name(name)
+			}
+			scalar, tagged, pk, many := false, false, false, false
+			readonly, omitempty := false, false
+			childPrefix := prefix
 			if tag != "" {
 				parts := strings.Split(tag, ",")
 				if parts[0] != "" {
 					name = parts[0]
+					tagged = true
 				}
 				for _, p := range parts[1:] {
-					if strings.TrimSpace(p) == "scalar" {
+					p = strings.TrimSpace(p)
+					switch {
+					case p == "scalar":
 						scalar = true
+					case p == "pk":
+						pk = true
+					case p == "many":
+						many = true
+					case p == "readonly":
+						readonly = true
+					case p == "omitempty":
+						omitempty = true
+					case strings.HasPrefix(p, "prefix="):
+						childPrefix += p[len("prefix="):]
 					}
 				}
 			}
 
+			if many {
+				// has-many collections are filled by aggregation, not by columns
+				continue
+			}
+			col := prefix + name
+			name = fm.key(col)
 			ft := f.Type
 
 			// Decide whether to flatten this field
@@ -928,7 +1342,7 @@ func fieldIndexMap(t reflect.Type) map[string]fieldInfo {
 				if nextT.Kind() == reflect.Pointer {
 					nextT = nextT.Elem()
 				}
-				walk(nextT, appendIndex(path, i))
+				walk(nextT, appendIndex(path, i), childPrefix)
 				continue
 			}
 
@@ -941,12 +1355,15 @@ func fieldIndexMap(t reflect.Type) map[string]fieldInfo {
 				// If already ambiguous, leave it as-is.
 				continue
 			}
-			m[name] = fieldInfo{index: appendIndex(path, i), scalar: scalar}
+			m[name] = fieldInfo{
+				index: appendIndex(path, i), col: col, scalar: scalar, tagged: tagged,
+				pk: pk, readonly: readonly, omitempty: omitempty,
+			}
 		}
 	}
 
-	walk(base, nil)
-	structIndexCache.put(t, m)
+	walk(base, nil, "")
+	cache.put(t, m)
 	return m
 }
 
@@ -985,8 +1402,13 @@ func appendIndex(path []int, idx int) []int {
 // fieldInfo describes a leaf field: its full index path and whether it's marked
 // as "scalar" via tag option (no slice expansion).
 type fieldInfo struct {
-	index     []int // full index path for FieldByIndex-like ops
+	index     []int  // full index path for FieldByIndex-like ops
+	col       string // column name as declared (before key() normalization)
 	scalar    bool
+	tagged    bool // name comes from an explicit `db:"name"` tag
+	pk        bool // `db:"name,pk"`: key of a has-many parent level and of Update/Delete
+	readonly  bool // `db:"name,readonly"`: never written by Insert/Update
+	omitempty bool // `db:"name,omitempty"`: not written by Insert/Update when zero
 	ambiguous bool // true if multiple fields with same name found (only for top-level fields)
 }
 
@@ -1000,12 +1422,13 @@ type fieldCache struct {
 }
 
 // newFieldCache creates a new simple two-tier cache with cheap rotation to limit memory usage.
+// The maps start empty and grow with use.
 func newFieldCache(max int) *fieldCache {
 	if max <= 0 {
 		max = cacheSize
 	}
 	return &fieldCache{
-		curr: make(map[reflect.Type]map[string]fieldInfo, max/2),
+		curr: make(map[reflect.Type]map[string]fieldInfo),
 		prev: make(map[reflect.Type]map[string]fieldInfo),
 		max:  max,
 	}
@@ -1090,6 +1513,36 @@ func readCols(q string, i int) (int, []string, bool) {
 	return -1, nil, false
 }
 
+// readOracleQuote detects an Oracle alternative-quoting opener q'<delim> at
+// q[i] and returns the closing sequence: the matching bracket (or the same
+// character) followed by a quote. The q must not end an identifier, except
+// for the national-character prefix N.
+func readOracleQuote(q string, i int) (closing string, ok bool) {
+	if i+2 >= len(q) || q[i+1] != '\'' {
+		return "", false
+	}
+	if i > 0 && isAlphaNumUnderscore(q[i-1]) {
+		n := q[i-1]
+		if n != 'n' && n != 'N' || i > 1 && isAlphaNumUnderscore(q[i-2]) {
+			return "", false
+		}
+	}
+	d := q[i+2]
+	switch d {
+	case '[':
+		return "]'", true
+	case '{':
+		return "}'", true
+	case '(':
+		return ")'", true
+	case '<':
+		return ">'", true
+	case ' ', '\t', '\n', '\r', '\'':
+		return "", false
+	}
+	return string(d) + "'", true
+}
+
 // readDollarTag detects a dollar-quoted opening tag ("$tag$") at the start of s.
 // It returns the full tag (e.g. "$tag$") and true if found.
 func readDollarTag(s string) (string, bool) {
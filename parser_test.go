@@ -2,6 +2,7 @@ package sqlr
 
 import (
 	"bytes"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -402,6 +403,8 @@ func TestDynamicQueries_AllDialects(t *testing.T) {
 				mustContainInOrder(t, q2, "$1", "$2", "$3")
 			case SQLServer:
 				mustContainInOrder(t, q2, "@p1", "@p2", "@p3")
+			case Oracle:
+				mustContainInOrder(t, q2, ":1", ":2", ":3")
 			default: // MySQL, SQLite
 				if got := strings.Count(q2, "?"); got != 3 {
 					t.Fatalf("count '?'=%d, want 3\n%s", got, q2)
@@ -894,6 +897,51 @@ func TestRows_HeterogeneousStructs_AllDialects(t *testing.T) {
 	}
 }
 
+// TestRows_TupleIN_AllDialects verifies :name{...} inside IN (...) expands to a
+// list of row values for composite-key lookups, from []struct and []map alike.
+func TestRows_TupleIN_AllDialects(t *testing.T) {
+	type key struct {
+		TenantID int `db:"tenant_id"`
+		ID       int `db:"id"`
+	}
+	want := map[Dialect]string{
+		Postgres:  "SELECT * FROM t WHERE (tenant_id, id) IN (($1, $2), ($3, $4))",
+		MySQL:     "SELECT * FROM t WHERE (tenant_id, id) IN ((?, ?), (?, ?))",
+		SQLite:    "SELECT * FROM t WHERE (tenant_id, id) IN ((?, ?), (?, ?))",
+		SQLServer: "SELECT * FROM t WHERE (tenant_id, id) IN ((@p1, @p2), (@p3, @p4))",
+		Oracle:    "SELECT * FROM t WHERE (tenant_id, id) IN ((:1, :2), (:3, :4))",
+	}
+	inputs := []any{
+		[]key{{1, 10}, {2, 20}},
+		[]*key{{1, 10}, {2, 20}},
+		[]map[string]any{{"tenant_id": 1, "id": 10}, {"id": 20, "tenant_id": 2}},
+	}
+	for _, dc := range allDialects() {
+		for _, keys := range inputs {
+			out, args, err := New(dc.d).
+				Write("SELECT * FROM t WHERE (tenant_id, id) IN (:keys{tenant_id,id})").
+				Bind("keys", keys).
+				Build()
+			assertNoError(t, err)
+			if out != want[dc.d] {
+				t.Fatalf("[%s] unexpected SQL:\n got=%s\nwant=%s", dc.name, out, want[dc.d])
+			}
+			assertArgsEqual(t, args, []any{1, 10, 2, 20})
+		}
+	}
+
+	// Joining a VALUES list works where row values are not allowed in IN.
+	out, args, err := New(SQLServer).
+		Write("SELECT t.* FROM t JOIN (VALUES :keys{tenant_id,id}) k(tenant_id, id) ON t.tenant_id = k.tenant_id AND t.id = k.id").
+		Bind("keys", []key{{1, 10}}).
+		Build()
+	assertNoError(t, err)
+	if out != "SELECT t.* FROM t JOIN (VALUES (@p1, @p2)) k(tenant_id, id) ON t.tenant_id = k.tenant_id AND t.id = k.id" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, 10})
+}
+
 // TestDollarQuoted_EmptyTag_Closed_AllDialects verifies that content inside $$...$$
 // is ignored by the parser and placeholders there are not bound.
 func TestDollarQuoted_EmptyTag_Closed_AllDialects(t *testing.T) {
@@ -1310,7 +1358,7 @@ func TestManyPlaceholders_NoAllocExplosion(t *testing.T) {
 	}
 }
 
-// TestGetColValue_MapKeyConversion_Direct performs table-driven checks for getColValue()
+// TestGetColValue_MapKeyConversion_Direct performs table-driven checks for defaultMapper.getColValue()
 // covering key conversions (defined vs alias), negatives, and []byte integrity.
 func TestGetColValue_MapKeyConversion_Direct(t *testing.T) {
 	type DefStr string     // defined type over string (requires Convert())
@@ -1384,7 +1432,7 @@ func TestGetColValue_MapKeyConversion_Direct(t *testing.T) {
 	for _, tc := range cases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			got, ok := getColValue(tc.row, tc.col)
+			got, ok := defaultMapper.getColValue(tc.row, tc.col)
 			if ok != tc.ok {
 				t.Fatalf("ok=%v, want %v (row=%T, col=%q)", ok, tc.ok, tc.row, tc.col)
 			}
@@ -1563,28 +1611,28 @@ func TestGetColValue_UnwrapPointerAndInterface(t *testing.T) {
 	s := &S{A: 7, B: "x"}
 
 	// *S -> unwrap pointer -> struct branch
-	v, ok := getColValue(s, "a")
+	v, ok := defaultMapper.getColValue(s, "a")
 	if !ok || v.(int) != 7 {
 		t.Fatalf("pointer struct: got=%v ok=%v, want 7 true", v, ok)
 	}
 
 	// **S -> unwrap chain (pointer to pointer)
 	ps := &s
-	v, ok = getColValue(ps, "B")
+	v, ok = defaultMapper.getColValue(ps, "B")
 	if !ok || v.(string) != "x" {
 		t.Fatalf("double pointer: got=%v ok=%v, want 'x' true", v, ok)
 	}
 
 	// interface{} that contains *S
 	var anyRow any = s
-	v, ok = getColValue(anyRow, "a")
+	v, ok = defaultMapper.getColValue(anyRow, "a")
 	if !ok || v.(int) != 7 {
 		t.Fatalf("interface wrapping *S: got=%v ok=%v, want 7 true", v, ok)
 	}
 
 	// nil pointer -> ok=false, no panic
 	var nilS *S
-	if v, ok = getColValue(nilS, "a"); ok {
+	if v, ok = defaultMapper.getColValue(nilS, "a"); ok {
 		t.Fatalf("nil pointer must return ok=false, got v=%v", v)
 	}
 }
@@ -1599,17 +1647,17 @@ func TestGetColValue_Struct_HitAndMiss(t *testing.T) {
 	s := S{A: 10, B: "ok"}
 
 	// hit on `db` tag
-	v, ok := getColValue(s, "a")
+	v, ok := defaultMapper.getColValue(s, "a")
 	if !ok || v.(int) != 10 {
 		t.Fatalf("struct hit db tag: got=%v ok=%v", v, ok)
 	}
 	// hit on field name
-	v, ok = getColValue(s, "B")
+	v, ok = defaultMapper.getColValue(s, "B")
 	if !ok || v.(string) != "ok" {
 		t.Fatalf("struct hit by name: got=%v ok=%v", v, ok)
 	}
 	// miss -> ok=false
-	if v, ok = getColValue(s, "missing"); ok {
+	if v, ok = defaultMapper.getColValue(s, "missing"); ok {
 		t.Fatalf("struct miss must be ok=false, got v=%v", v)
 	}
 }
@@ -1620,20 +1668,20 @@ func TestGetColValue_MapPointer_InterfaceAndNil(t *testing.T) {
 	// pointer to map[string]any (skips map[string]any fast-path; goes through reflect)
 	m := map[string]any{"a": 1, "b": []byte{1, 2}}
 	mp := &m
-	v, ok := getColValue(mp, "a")
+	v, ok := defaultMapper.getColValue(mp, "a")
 	if !ok || v.(int) != 1 {
 		t.Fatalf("pointer to map: got=%v ok=%v, want 1 true", v, ok)
 	}
 	// interface{} wrapping *map
 	var anyMap any = mp
-	v, ok = getColValue(anyMap, "b")
+	v, ok = defaultMapper.getColValue(anyMap, "b")
 	if !ok || !bytes.Equal(v.([]byte), []byte{1, 2}) {
 		t.Fatalf("interface wrapping *map: got=%v ok=%v", v, ok)
 	}
 
 	// nil *map -> ok=false
 	var nilMap *map[string]any
-	if v, ok = getColValue(nilMap, "a"); ok {
+	if v, ok = defaultMapper.getColValue(nilMap, "a"); ok {
 		t.Fatalf("nil *map must return ok=false, got v=%v", v)
 	}
 }
@@ -1642,11 +1690,11 @@ func TestGetColValue_MapPointer_InterfaceAndNil(t *testing.T) {
 // returns ok=false for primitives and unsupported types like generic slices.
 func TestGetColValue_DefaultBranch_PrimitiveAndUnsupported(t *testing.T) {
 	// primitive type -> default: ok=false
-	if v, ok := getColValue(123, "a"); ok {
+	if v, ok := defaultMapper.getColValue(123, "a"); ok {
 		t.Fatalf("primitive row must return ok=false, got v=%v", v)
 	}
 	// generic slice -> default: ok=false
-	if v, ok := getColValue([]int{1, 2}, "a"); ok {
+	if v, ok := defaultMapper.getColValue([]int{1, 2}, "a"); ok {
 		t.Fatalf("slice row must return ok=false, got v=%v", v)
 	}
 }
@@ -1675,17 +1723,17 @@ func TestFieldIndexMap_BaseNotStruct_ReturnsEmpty(t *testing.T) {
 	}
 
 	for _, tt := range types {
-		m := fieldIndexMap(tt)
+		m := defaultMapper.fieldIndexMap(tt)
 		if m == nil {
-			t.Fatalf("fieldIndexMap(%v) returned nil", tt)
+			t.Fatalf("defaultMapper.fieldIndexMap(%v) returned nil", tt)
 		}
 		if len(m) != 0 {
-			t.Fatalf("fieldIndexMap(%v) len=%d, want 0", tt, len(m))
+			t.Fatalf("defaultMapper.fieldIndexMap(%v) len=%d, want 0", tt, len(m))
 		}
 		// second call (cache hit) must give the same result
-		m2 := fieldIndexMap(tt)
+		m2 := defaultMapper.fieldIndexMap(tt)
 		if len(m2) != 0 {
-			t.Fatalf("fieldIndexMap(%v) (2nd) len=%d, want 0", tt, len(m2))
+			t.Fatalf("defaultMapper.fieldIndexMap(%v) (2nd) len=%d, want 0", tt, len(m2))
 		}
 	}
 }
@@ -1951,6 +1999,578 @@ func TestStructTag_Scalar_NoExpand_AllDialects(t *testing.T) {
 	}
 }
 
+// --------------------------------
+// Tests: prefixed flattening
+// --------------------------------
+
+type prefUser struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type prefOrder struct {
+	ID    int `db:"id"`
+	Total float64
+}
+
+type prefRow struct {
+	User  prefUser   `db:",prefix=u_"`
+	Order *prefOrder `db:",prefix=o_"`
+}
+
+// TestFieldIndexMap_Prefix verifies that `db:",prefix=..."` names the flattened children
+// of a nested struct, that prefixes concatenate across levels, and that the same type can
+// be embedded twice under different prefixes without ambiguity.
+func TestFieldIndexMap_Prefix(t *testing.T) {
+	type Pair struct {
+		Buyer  prefUser `db:",prefix=b_"`
+		Seller struct {
+			Who prefUser `db:",prefix=who_"`
+		} `db:",prefix=s_"`
+	}
+	m := defaultMapper.fieldIndexMap(reflect.TypeOf(Pair{}))
+	for _, k := range []string{"b_id", "b_name", "s_who_id", "s_who_name"} {
+		fi, ok := m[k]
+		if !ok || fi.ambiguous {
+			t.Fatalf("key %q missing or ambiguous in %v", k, m)
+		}
+	}
+	if _, ok := m["id"]; ok {
+		t.Fatalf("unprefixed key must not exist")
+	}
+
+	m = defaultMapper.fieldIndexMap(reflect.TypeOf(prefRow{}))
+	if fi := m["o_Total"]; !reflect.DeepEqual(fi.index, []int{1, 1}) {
+		t.Fatalf("o_Total index=%v", fi.index)
+	}
+}
+
+// TestPrefix_BindAndRows_AllDialects verifies that prefixed names resolve in Bind(struct)
+// lookups and in :name{...} row blocks.
+func TestPrefix_BindAndRows_AllDialects(t *testing.T) {
+	r := prefRow{User: prefUser{ID: 1, Name: "a"}, Order: &prefOrder{ID: 9, Total: 2.5}}
+	for _, dc := range allDialects() {
+		_, args, err := New(dc.d).Write("SELECT :u_id, :u_name, :o_id, :o_Total").Bind(r).Build()
+		assertNoError(t, err)
+		assertArgsEqual(t, args, []any{1, "a", 9, 2.5})
+
+		_, args, err = New(dc.d).Write("INSERT INTO t(uid, oid) VALUES :rows{u_id,o_id}").
+			Bind("rows", []prefRow{r, {User: prefUser{ID: 2}, Order: &prefOrder{ID: 8}}}).Build()
+		assertNoError(t, err)
+		assertArgsEqual(t, args, []any{1, 9, 2, 8})
+	}
+}
+
+// --------------------------------
+// Tests: placeholder reuse
+// --------------------------------
+
+// TestReuseParams_NumberedDialects verifies that repeated scalar names reuse the first
+// ordinal and bind the value once on Postgres and SQL Server, while slices still expand.
+func TestReuseParams_NumberedDialects(t *testing.T) {
+	const q = "SELECT :doc WHERE a=:x OR b=:x AND id IN (:ids) AND c IN (:ids) AND d=:doc"
+	bind := P{"doc": `{"big":"json"}`, "x": 1, "ids": []int{7, 8}}
+
+	out, args, err := New(Postgres, Config{ReuseParams: true}).Write(q).Bind(bind).Build()
+	assertNoError(t, err)
+	if out != "SELECT $1 WHERE a=$2 OR b=$2 AND id IN ($3, $4) AND c IN ($5, $6) AND d=$1" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{`{"big":"json"}`, 1, 7, 8, 7, 8})
+
+	out, args, err = New(SQLServer, Config{ReuseParams: true}).Write("SELECT :a, :b, :a").Bind("a", 1, "b", 2).Build()
+	assertNoError(t, err)
+	if out != "SELECT @p1, @p2, @p1" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, 2})
+}
+
+// TestReuseParams_OtherDialectsAndDefault verifies that positional dialects and the
+// default configuration keep one placeholder per occurrence.
+func TestReuseParams_OtherDialectsAndDefault(t *testing.T) {
+	for _, d := range []Dialect{MySQL, SQLite} {
+		out, args, err := New(d, Config{ReuseParams: true}).Write("SELECT :a, :a").Bind("a", 1).Build()
+		assertNoError(t, err)
+		if out != "SELECT ?, ?" {
+			t.Fatalf("[%s] unexpected SQL: %s", d, out)
+		}
+		assertArgsEqual(t, args, []any{1, 1})
+	}
+	out, args, err := New(Postgres).Write("SELECT :a, :a").Bind("a", 1).Build()
+	assertNoError(t, err)
+	if out != "SELECT $1, $2" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, 1})
+}
+
+// TestReuseParams_SavesBudget verifies reused placeholders do not count against MaxParams.
+func TestReuseParams_SavesBudget(t *testing.T) {
+	s := New(Postgres, Config{ReuseParams: true, MaxParams: 2})
+	st, err := s.Compile("SELECT :a, :b, :a, :b, :a")
+	assertNoError(t, err)
+	out, args, err := st.Bind("a", 1, "b", 2).Build()
+	assertNoError(t, err)
+	if out != "SELECT $1, $2, $1, $2, $1" || len(args) != 2 {
+		t.Fatalf("unexpected: %s %v", out, args)
+	}
+}
+
+// --------------------------------
+// Tests: named args
+// --------------------------------
+
+// TestNamedArgs_SQLServerAndSQLite verifies @name rendering with sql.NamedArg args,
+// suffixed slice expansions, rows-block names (by position for dotted or spaced
+// columns), and single binding of repeated names.
+func TestNamedArgs_SQLServerAndSQLite(t *testing.T) {
+	const q = "SELECT * FROM t WHERE c=:customer_id AND id IN (:ids) OR p=:customer_id OR id IN (:ids)"
+	for _, d := range []Dialect{SQLServer, SQLite} {
+		out, args, err := New(d, Config{NamedArgs: true}).Write(q).
+			Bind("customer_id", 42, "ids", []int{7, 8}).Build()
+		assertNoError(t, err)
+		want := "SELECT * FROM t WHERE c=@customer_id AND id IN (@ids_1, @ids_2) OR p=@customer_id OR id IN (@ids_1, @ids_2)"
+		if out != want {
+			t.Fatalf("[%s] unexpected SQL:\n got=%s\nwant=%s", d, out, want)
+		}
+		assertArgsEqual(t, args, []any{
+			sql.Named("customer_id", 42), sql.Named("ids_1", 7), sql.Named("ids_2", 8),
+		})
+	}
+
+	type Row struct {
+		A int    `db:"a"`
+		B string `db:"b"`
+	}
+	out, args, err := New(SQLServer, Config{NamedArgs: true}).
+		Write("INSERT INTO t(a,b) VALUES :rows{a,b}").Bind("rows", []Row{{1, "x"}, {2, "y"}}).Build()
+	assertNoError(t, err)
+	if out != "INSERT INTO t(a,b) VALUES (@rows_1_a, @rows_1_b), (@rows_2_a, @rows_2_b)" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{
+		sql.Named("rows_1_a", 1), sql.Named("rows_1_b", "x"), sql.Named("rows_2_a", 2), sql.Named("rows_2_b", "y"),
+	})
+
+	// Columns that cannot end a parameter name are suffixed by position.
+	out, args, err = New(SQLServer, Config{NamedArgs: true}).
+		Write("VALUES :rows{a, x.b, x y}").Bind("rows", []map[string]any{{"a": 1, "x.b": 2, "x y": 3}}).Build()
+	assertNoError(t, err)
+	if out != "VALUES (@rows_1_a, @rows_1_2, @rows_1_3)" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{sql.Named("rows_1_a", 1), sql.Named("rows_1_2", 2), sql.Named("rows_1_3", 3)})
+}
+
+// TestNamedArgs_Conflict verifies that a generated name matching another
+// placeholder fails instead of binding two args with the same name.
+func TestNamedArgs_Conflict(t *testing.T) {
+	type Row struct {
+		A int `db:"a"`
+	}
+	s := New(SQLServer, Config{NamedArgs: true})
+	for _, tc := range []struct {
+		sql  string
+		args []any
+	}{
+		{"SELECT :ids, :ids_1", []any{"ids", []int{1, 2}, "ids_1", 9}},
+		{"SELECT :ids_1, :ids", []any{"ids", []int{1, 2}, "ids_1", 9}},
+		{"VALUES :rows{a}, (:rows_1_a)", []any{"rows", []Row{{1}}, "rows_1_a", 9}},
+	} {
+		if _, _, err := s.Write(tc.sql).Bind(tc.args...).Build(); !errors.Is(err, ErrNamedConflict) {
+			t.Fatalf("%q: want ErrNamedConflict, got %v", tc.sql, err)
+		}
+	}
+}
+
+// TestNamedArgs_IgnoredByOtherDialects verifies that dialects without named parameters
+// keep their positional placeholders.
+func TestNamedArgs_IgnoredByOtherDialects(t *testing.T) {
+	for _, d := range []Dialect{Postgres, MySQL} {
+		out, args, err := New(d, Config{NamedArgs: true}).Write("SELECT :a").Bind("a", 1).Build()
+		assertNoError(t, err)
+		if strings.Contains(out, "@a") {
+			t.Fatalf("[%s] unexpected named placeholder: %s", d, out)
+		}
+		assertArgsEqual(t, args, []any{1})
+	}
+}
+
+// --------------------------------
+// Tests: Oracle
+// --------------------------------
+
+// TestOracle_PlaceholdersAndDefaults verifies :1-style ordinals and the default MaxParams.
+func TestOracle_PlaceholdersAndDefaults(t *testing.T) {
+	s := New(Oracle)
+	out, args, err := s.Write("SELECT * FROM t WHERE a=:a AND id IN (:ids)").Bind("a", "x", "ids", []int{1, 2}).Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE a=:1 AND id IN (:2, :3)" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{"x", 1, 2})
+	if s.config.MaxParams != 65535 || Oracle.String() != "oracle" {
+		t.Fatalf("MaxParams=%d String=%q", s.config.MaxParams, Oracle.String())
+	}
+}
+
+// TestOracle_AlternativeQuoting verifies that colons inside q'[...]'-style literals
+// (any delimiter, Q and nq prefixes) are not parameters, and that identifiers ending
+// in q are not mistaken for the opener.
+func TestOracle_AlternativeQuoting(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"SELECT q'[it's :no]' , :a FROM dual", "SELECT q'[it's :no]' , :1 FROM dual"},
+		{"SELECT Q'{a}:no}' || q'(:no)' || q'<:no>' FROM t WHERE x=:a", "SELECT Q'{a}:no}' || q'(:no)' || q'<:no>' FROM t WHERE x=:1"},
+		{"SELECT q'!:no'!' , nq'#:no#', N'x' FROM t WHERE x=:a", "SELECT q'!:no'!' , nq'#:no#', N'x' FROM t WHERE x=:1"},
+		{"SELECT seq'x', :a FROM t", "SELECT seq'x', :1 FROM t"},
+	}
+	for _, tc := range tests {
+		out, args, err := New(Oracle).Write(tc.in).Bind("a", 1).Build()
+		assertNoError(t, err)
+		if out != tc.want {
+			t.Fatalf("unexpected SQL:\n got=%s\nwant=%s", out, tc.want)
+		}
+		assertArgsEqual(t, args, []any{1})
+	}
+
+	// Other dialects do not know q-quoting: the colon is a regular placeholder.
+	out, _, err := New(Postgres).Write("SELECT q'[x]' || :a").Bind("a", 1).Build()
+	assertNoError(t, err)
+	if out != "SELECT q'[x]' || $1" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+}
+
+// --------------------------------
+// Tests: identifiers
+// --------------------------------
+
+// TestIdent_QuotedPerDialect verifies :!name and Ident values render as quoted,
+// escaped identifiers (dotted names part by part) without binding args.
+func TestIdent_QuotedPerDialect(t *testing.T) {
+	want := map[Dialect]string{
+		Postgres:  `SELECT "a""b", "x", "y" FROM "public"."users" WHERE id=$1 ORDER BY "name"`,
+		MySQL:     "SELECT `a\"b`, `x`, `y` FROM `public`.`users` WHERE id=? ORDER BY `name`",
+		SQLite:    `SELECT "a""b", "x", "y" FROM "public"."users" WHERE id=? ORDER BY "name"`,
+		SQLServer: `SELECT [a"b], [x], [y] FROM [public].[users] WHERE id=@p1 ORDER BY [name]`,
+		Oracle:    `SELECT "a""b", "x", "y" FROM "public"."users" WHERE id=:1 ORDER BY "name"`,
+	}
+	for _, dc := range allDialects() {
+		out, args, err := New(dc.d).
+			Write("SELECT :!col, :!cols FROM :table WHERE id=:id ORDER BY :!sort").
+			Bind("col", `a"b`, "cols", []Ident{"x", "y"}, "table", Ident("public.users"), "id", 7, "sort", "name").
+			Build()
+		assertNoError(t, err)
+		if out != want[dc.d] {
+			t.Fatalf("[%s] unexpected SQL:\n got=%s\nwant=%s", dc.name, out, want[dc.d])
+		}
+		assertArgsEqual(t, args, []any{7})
+	}
+
+	// MySQL escapes backticks by doubling, SQL Server closing brackets.
+	out, _, err := New(MySQL).Write("SELECT :!c").Bind("c", "a`b").Build()
+	assertNoError(t, err)
+	if out != "SELECT `a``b`" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	out, _, err = New(SQLServer).Write("SELECT :!c").Bind("c", "a]b").Build()
+	assertNoError(t, err)
+	if out != "SELECT [a]]b]" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+}
+
+// TestIdent_Errors verifies invalid names, non-string values, missing binds and
+// that :! inside literals or before a non-name is left alone.
+func TestIdent_Errors(t *testing.T) {
+	s := New(Postgres)
+	for _, v := range []any{"", "a..b", ".a", "a.", []string{}, 42} {
+		_, _, err := s.Write("SELECT :!c").Bind("c", v).Build()
+		if !errors.Is(err, ErrIdentInvalid) && !errors.Is(err, ErrSliceEmpty) {
+			t.Fatalf("%#v: want ErrIdentInvalid or ErrSliceEmpty, got %v", v, err)
+		}
+	}
+	if _, _, err := s.Write("SELECT :!c").Build(); !errors.Is(err, ErrParamMissing) {
+		t.Fatalf("want ErrParamMissing, got %v", err)
+	}
+	out, _, err := s.Write("SELECT ':!c', a:!=b").Build()
+	assertNoError(t, err)
+	if out != "SELECT ':!c', a:!=b" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+}
+
+// TestIdent_AllowList verifies Config.AllowedIdents for :!name and Ident values,
+// in regular and compiled statements.
+func TestIdent_AllowList(t *testing.T) {
+	s := New(Postgres, Config{AllowedIdents: []string{"name", "created_at", "public.users"}})
+	out, _, err := s.Write("SELECT * FROM :t ORDER BY :!sort").Bind("t", Ident("public.users"), "sort", "created_at").Build()
+	assertNoError(t, err)
+	if out != `SELECT * FROM "public"."users" ORDER BY "created_at"` {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+
+	for _, v := range []any{"password", Ident("users"), []string{"name", "secret"}} {
+		_, _, err := s.Write("SELECT :!c").Bind("c", v).Build()
+		if !errors.Is(err, ErrIdentNotAllowed) {
+			t.Fatalf("%#v: want ErrIdentNotAllowed, got %v", v, err)
+		}
+	}
+
+	st, err := s.Compile("SELECT id FROM t ORDER BY :!sort DESC")
+	assertNoError(t, err)
+	if _, _, err := st.Bind("sort", "id; DROP TABLE t").Build(); !errors.Is(err, ErrIdentNotAllowed) {
+		t.Fatalf("want ErrIdentNotAllowed, got %v", err)
+	}
+	out, args, err := st.Bind("sort", "name").Build()
+	assertNoError(t, err)
+	if out != `SELECT id FROM t ORDER BY "name" DESC` || len(args) != 0 {
+		t.Fatalf("unexpected SQL: %s %v", out, args)
+	}
+}
+
+// --------------------------------
+// Tests: empty slices
+// --------------------------------
+
+// TestEmptySlices_Policies verifies Config.EmptySlices per dialect and that other
+// placeholders keep their ordinals.
+func TestEmptySlices_Policies(t *testing.T) {
+	falseSQL := map[Dialect]string{
+		Postgres:  "SELECT * FROM t WHERE id IN (SELECT NULL WHERE 1=0) AND a=$1",
+		MySQL:     "SELECT * FROM t WHERE id IN (SELECT NULL FROM dual WHERE 1=0) AND a=?",
+		SQLite:    "SELECT * FROM t WHERE id IN (SELECT NULL WHERE 1=0) AND a=?",
+		SQLServer: "SELECT * FROM t WHERE id IN (SELECT NULL WHERE 1=0) AND a=@p1",
+		Oracle:    "SELECT * FROM t WHERE id IN (SELECT NULL FROM dual WHERE 1=0) AND a=:1",
+	}
+	for _, dc := range allDialects() {
+		_, _, err := New(dc.d).Write("SELECT * FROM t WHERE id IN (:ids)").Bind("ids", []int{}).Build()
+		if !errors.Is(err, ErrSliceEmpty) {
+			t.Fatalf("[%s] want ErrSliceEmpty, got %v", dc.name, err)
+		}
+
+		out, args, err := New(dc.d, Config{EmptySlices: EmptyNull}).
+			Write("SELECT * FROM t WHERE id IN (:ids)").Bind("ids", []int(nil)).Build()
+		assertNoError(t, err)
+		if out != "SELECT * FROM t WHERE id IN (NULL)" || len(args) != 0 {
+			t.Fatalf("[%s] unexpected SQL: %s %v", dc.name, out, args)
+		}
+
+		out, args, err = New(dc.d, Config{EmptySlices: EmptyFalse}).
+			Write("SELECT * FROM t WHERE id IN (:ids) AND a=:a").Bind("ids", []string{}, "a", 1).Build()
+		assertNoError(t, err)
+		if out != falseSQL[dc.d] {
+			t.Fatalf("[%s] unexpected SQL:\n got=%s\nwant=%s", dc.name, out, falseSQL[dc.d])
+		}
+		assertArgsEqual(t, args, []any{1})
+	}
+
+	// Registered dialects follow Syntax.FromDual.
+	for d, want := range map[Dialect]string{mysqlCompatDialect: "FROM dual", testDialect: "SELECT NULL WHERE"} {
+		out, _, err := New(d, Config{EmptySlices: EmptyFalse}).Write("SELECT :ids").Bind("ids", []int{}).Build()
+		assertNoError(t, err)
+		if !strings.Contains(out, want) {
+			t.Fatalf("[%s] unexpected SQL: %s", d, out)
+		}
+	}
+}
+
+// TestEmptySlices_EmptyAs verifies the per-value override in both directions and
+// that non-empty wrapped slices expand as usual.
+func TestEmptySlices_EmptyAs(t *testing.T) {
+	s := New(Postgres)
+	out, _, err := s.Write("SELECT * FROM t WHERE id NOT IN (:ids)").Bind("ids", EmptyAs([]int{}, EmptyFalse)).Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE id NOT IN (SELECT NULL WHERE 1=0)" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+
+	out, args, err := s.Write("SELECT * FROM t WHERE id IN (:ids)").Bind("ids", EmptyAs([]int{1, 2}, EmptyNull)).Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE id IN ($1, $2)" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, 2})
+
+	lax := New(Postgres, Config{EmptySlices: EmptyNull})
+	_, _, err = lax.Write("SELECT * FROM t WHERE id IN (:ids)").Bind("ids", EmptyAs([]int{}, EmptyError)).Build()
+	if !errors.Is(err, ErrSliceEmpty) {
+		t.Fatalf("want ErrSliceEmpty, got %v", err)
+	}
+}
+
+// --------------------------------
+// Tests: optional sections
+// --------------------------------
+
+// TestSections_BoundOrDropped verifies /*? ... */ sections are kept (without
+// delimiters) when all their placeholders are bound and dropped otherwise, with
+// ordinals following the emitted placeholders only.
+func TestSections_BoundOrDropped(t *testing.T) {
+	const q = "SELECT * FROM t WHERE 1=1 /*? AND name = :name */ /*? AND id IN (:ids) AND kind = :kind */ AND a = :a"
+	tests := []struct {
+		bind P
+		want string
+		args []any
+	}{
+		{P{"a": 1}, "SELECT * FROM t WHERE 1=1   AND a = $1", []any{1}},
+		{P{"a": 1, "name": "x"}, "SELECT * FROM t WHERE 1=1  AND name = $1   AND a = $2", []any{"x", 1}},
+		{P{"a": 1, "ids": []int{7, 8}}, "SELECT * FROM t WHERE 1=1   AND a = $1", []any{1}},
+		{P{"a": 1, "ids": []int{7, 8}, "kind": "k"}, "SELECT * FROM t WHERE 1=1   AND id IN ($1, $2) AND kind = $3  AND a = $4", []any{7, 8, "k", 1}},
+		{P{"a": 1, "name": nil}, "SELECT * FROM t WHERE 1=1  AND name = $1   AND a = $2", []any{nil, 1}},
+	}
+	s := New(Postgres)
+	st, err := s.Compile(q)
+	assertNoError(t, err)
+	for _, tc := range tests {
+		for _, b := range []*Builder{s.Write(q), st.Bind()} {
+			out, args, err := b.Bind(tc.bind).Build()
+			assertNoError(t, err)
+			if out != tc.want {
+				t.Fatalf("bind %v:\n got=%s\nwant=%s", tc.bind, out, tc.want)
+			}
+			assertArgsEqual(t, args, tc.args)
+		}
+	}
+}
+
+// TestSections_Lexing verifies that quotes and comments inside sections are
+// respected, that /*? inside literals is text, and malformed sections error.
+func TestSections_Lexing(t *testing.T) {
+	for _, dc := range allDialects() {
+		out, args, err := New(dc.d).
+			Write("SELECT '/*? :x */' /*? , ':no */' /* c */ , :b */ FROM t").
+			Bind("b", 2).
+			Build()
+		assertNoError(t, err)
+		if !strings.HasPrefix(out, "SELECT '/*? :x */'  , ':no */' /* c */ , ") || !strings.HasSuffix(out, "  FROM t") {
+			t.Fatalf("[%s] unexpected SQL: %s", dc.name, out)
+		}
+		assertArgsEqual(t, args, []any{2})
+	}
+
+	s := New(MySQL)
+	for _, q := range []string{"SELECT 1 /*? AND a = :a", "SELECT 1 /*? /*? :a */ */", "SELECT 1 /*? '*/"} {
+		if _, _, err := s.Write(q).Bind("a", 1).Build(); !errors.Is(err, ErrSectionMalformed) {
+			t.Fatalf("%q: want ErrSectionMalformed, got %v", q, err)
+		}
+		if _, err := s.Compile(q); !errors.Is(err, ErrSectionMalformed) {
+			t.Fatalf("%q: Compile: want ErrSectionMalformed, got %v", q, err)
+		}
+	}
+}
+
+// TestSections_RowsBlocks verifies that a section with a rows-block is kept
+// when the rows are bound as the input itself, and dropped without rows.
+func TestSections_RowsBlocks(t *testing.T) {
+	type Row struct {
+		A int `db:"a"`
+	}
+	const q = "INSERT INTO t (a) VALUES (0)/*? , :rows{a} */"
+	s := New(Postgres)
+	st, err := s.Compile(q)
+	assertNoError(t, err)
+	for _, b := range []*Builder{s.Write(q), st.Bind()} {
+		out, args, err := b.Bind([]Row{{1}, {2}}).Build()
+		assertNoError(t, err)
+		if out != "INSERT INTO t (a) VALUES (0) , ($1), ($2) " {
+			t.Fatalf("unexpected SQL: %q", out)
+		}
+		assertArgsEqual(t, args, []any{1, 2})
+	}
+	out, _, err := s.Write(q).Bind(P{"x": 1}).Build()
+	assertNoError(t, err)
+	if out != "INSERT INTO t (a) VALUES (0)" {
+		t.Fatalf("unexpected SQL: %q", out)
+	}
+	out, _, err = New(Postgres, Config{SkipZeroSections: true}).Write(q).Bind([]Row{}).Build()
+	assertNoError(t, err)
+	if out != "INSERT INTO t (a) VALUES (0)" {
+		t.Fatalf("unexpected SQL: %q", out)
+	}
+}
+
+// TestSections_SkipZero verifies Config.SkipZeroSections drops sections bound to
+// nil, zero values and empty slices.
+func TestSections_SkipZero(t *testing.T) {
+	s := New(SQLServer, Config{SkipZeroSections: true})
+	const q = "SELECT * FROM t WHERE 1=1 /*? AND name = :name */ /*? AND id IN (:ids) */ /*? AND age > :age */"
+	out, args, err := s.Write(q).Bind("name", "", "ids", []int{}, "age", (*int)(nil)).Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE 1=1   " || len(args) != 0 {
+		t.Fatalf("unexpected SQL: %q %v", out, args)
+	}
+	out, args, err = s.Write(q).Bind("name", "x", "ids", []int{1}, "age", 0).Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE 1=1  AND name = @p1   AND id IN (@p2)  " {
+		t.Fatalf("unexpected SQL: %q", out)
+	}
+	assertArgsEqual(t, args, []any{"x", 1})
+}
+
+// --------------------------------
+// Tests: build errors
+// --------------------------------
+
+// TestBuildError_Position verifies that emit and lexer errors carry the offset,
+// line, column and snippet of the failing occurrence and still match the sentinels.
+func TestBuildError_Position(t *testing.T) {
+	const q = "SELECT *\nFROM t\nWHERE a = :a\n\tAND b IN (:a, :b)"
+	tests := []struct {
+		name    string
+		q       string
+		bind    P
+		is      error
+		line    int
+		col     int
+		snippet string
+	}{
+		{"missing", q, P{"a": 1}, ErrParamMissing, 4, 16, "\tAND b IN (:a, :b)\n\t              ^"},
+		{"empty slice", q, P{"a": []int{}, "b": 1}, ErrSliceEmpty, 3, 11, "WHERE a = :a\n          ^"},
+		{"rows malformed", "INSERT INTO t\r\nVALUES :rows{a,", nil, ErrRowsMalformed, 2, 8, "VALUES :rows{a,\n       ^"},
+		{"unicode column", "SELECT 'héllo', :x", nil, ErrParamMissing, 1, 17, "SELECT 'héllo', :x\n                ^"},
+	}
+	for _, tc := range tests {
+		for _, compiled := range []bool{false, true} {
+			s := New(Postgres)
+			var err error
+			if compiled {
+				var st *Stmt
+				if st, err = s.Compile(tc.q); err == nil {
+					_, _, err = st.Bind(tc.bind).Build()
+				}
+			} else {
+				_, _, err = s.Write(tc.q).Bind(tc.bind).Build()
+			}
+			var be *BuildError
+			if !errors.As(err, &be) || !errors.Is(err, tc.is) {
+				t.Fatalf("%s: want *BuildError wrapping %v, got %v", tc.name, tc.is, err)
+			}
+			if be.Line != tc.line || be.Column != tc.col || be.Snippet != tc.snippet {
+				t.Fatalf("%s: got line=%d col=%d snippet=%q, want %d %d %q", tc.name, be.Line, be.Column, be.Snippet, tc.line, tc.col, tc.snippet)
+			}
+			if tc.q[be.Offset] != ':' {
+				t.Fatalf("%s: offset %d does not point at the placeholder", tc.name, be.Offset)
+			}
+			if want := fmt.Sprintf("(line %d, column %d)", tc.line, tc.col); !strings.HasSuffix(err.Error(), want) {
+				t.Fatalf("%s: message %q lacks %q", tc.name, err.Error(), want)
+			}
+		}
+	}
+
+	// Unclosed sections and quotes point at their opener.
+	_, err := Params(Postgres, "SELECT 1,\n  /*? 'open :x */")
+	var be *BuildError
+	if !errors.As(err, &be) || be.Line != 2 || be.Column != 3 || !errors.Is(err, ErrSectionMalformed) {
+		t.Fatalf("want section error at 2:3, got %v", err)
+	}
+	_, _, err = tokenize(New(MySQL), "SELECT 1,\n\t`open", true)
+	if !errors.As(err, &be) || be.Line != 2 || be.Column != 2 || !errors.Is(err, ErrUnterminated) {
+		t.Fatalf("want unterminated error at 2:2, got %v", err)
+	}
+}
+
 // --------------------------------
 // Tests: field cache
 // --------------------------------
@@ -2350,6 +2970,27 @@ func BenchmarkBind_Long_AllDialects(tb *testing.B) {
 
 var reRowsStart = regexp.MustCompile(`:[A-Za-z_][A-Za-z0-9_]*\{`)
 
+// stripSections returns sql without the /*? and */ delimiters of its
+// optional sections, as Build renders it when every section is kept, so that
+// placeholder counts compare the same text.
+func stripSections(sql string, d Dialect) string {
+	tokens, _, err := tokenize(New(d), sql, false)
+	if err != nil {
+		return sql
+	}
+	var sb strings.Builder
+	for i, tok := range tokens {
+		end := len(sql)
+		if i+1 < len(tokens) {
+			end = tokens[i+1].pos
+		}
+		if tok.kind != tkSection && tok.kind != tkSectionEnd {
+			sb.WriteString(sql[tok.pos:end])
+		}
+	}
+	return sb.String()
+}
+
 // FuzzBind_NoPanic_AllDialects fuzzes with real SQL-like fragments to ensure the parser never
 // panics and that the number of emitted placeholders equals len(args).
 func FuzzBind_NoPanic_AllDialects(tf *testing.F) {
@@ -2362,6 +3003,9 @@ func FuzzBind_NoPanic_AllDialects(tf *testing.F) {
 		"SELECT $q$ :x $q$ , $q1$ $q$ :y $q1$ , :z",
 		"SELECT $$ open :x , :y",
 		"SELECT $not$tag :x $ and :y",
+		"SELECT * FROM t WHERE 1=1 /*? AND a = :a */ /*? AND id IN (:ids) -- */\n */ AND b = ?",
+		"SELECT '/*? :x */' /*? , ':y */' /* c */ , :z */ /*? */",
+		"/*?*/*? $/*?*/1 :/*? */1",
 	}
 	for _, s := range seeds {
 		tf.Add(s)
@@ -2407,7 +3051,7 @@ func FuzzBind_NoPanic_AllDialects(tf *testing.F) {
 				t.Skip()
 			}
 			// Property: #emitted placeholders == len(args)
-			added := countNewPlaceholders(out, sql, dc.d)
+			added := countNewPlaceholders(out, stripSections(sql, dc.d), dc.d)
 			if added != len(args) {
 				t.Fatalf("[%s] added=%d, len(args)=%d\nSQL:\n%s\nOUT:\n%s\nBIND:%v",
 					dc.name, added, len(args), sql, out, bind)
@@ -2424,7 +3068,7 @@ func FuzzBind_TokenMixer_AllDialects(tf *testing.F) {
 		var sb strings.Builder
 		toks := []string{
 			"TEXT", "SQ", "DQ", "BT", "BR", "LC", "BC", "DLR",
-			"PH", "CAST", "JSON", "WS",
+			"PH", "CAST", "JSON", "WS", "SEC",
 		}
 		n := 5 + r.Intn(50)
 		for i := 0; i < n; i++ {
@@ -2462,6 +3106,8 @@ func FuzzBind_TokenMixer_AllDialects(tf *testing.F) {
 				sb.WriteString(" doc->>'k' ")
 			case "WS":
 				sb.WriteString(" ")
+			case "SEC":
+				sb.WriteString(" /*? AND c IN (:ids) '*/' -- */\n AND d = :a */")
 			}
 		}
 		return sb.String()
@@ -2486,17 +3132,23 @@ func FuzzBind_TokenMixer_AllDialects(tf *testing.F) {
 			"_u":  true,
 		}
 
-		// Add values for ANY :name present (if not already in bind)
-		re := regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+		// Add values for ANY :name present (if not already in bind);
+		// :!name identifiers get a column name.
+		re := regexp.MustCompile(`:(!?)([A-Za-z_][A-Za-z0-9_]*)`)
 		seen := map[string]struct{}{}
 		for _, m := range re.FindAllStringSubmatch(sql, -1) {
-			name := m[1]
+			name := m[2]
 			if _, ok := bind[name]; ok {
 				continue
 			}
 			if _, ok := seen[name]; ok {
 				continue
 			}
+			if m[1] == "!" {
+				bind[name] = "col"
+				seen[name] = struct{}{}
+				continue
+			}
 			// alternate types for some variability
 			idx := len(seen)
 			switch idx % 3 {
@@ -2514,12 +3166,14 @@ func FuzzBind_TokenMixer_AllDialects(tf *testing.F) {
 			out, args, err := New(dc.d).Write(sql).Bind(bind).Build()
 			if err != nil {
 				// fuzzer may generate names > MaxNameLen: OK to skip
-				if errors.Is(err, ErrParamNameTooLong) {
+				// the same name used as :x and :!x cannot bind both ways
+				// as do sections nested or left open by mutations
+				if errors.Is(err, ErrParamNameTooLong) || errors.Is(err, ErrIdentInvalid) || errors.Is(err, ErrSectionMalformed) {
 					t.Skip()
 				}
 				t.Fatalf("[%s] unexpected error: %v\nSQL:\n%s", dc.name, err, sql)
 			}
-			added := countNewPlaceholders(out, sql, dc.d)
+			added := countNewPlaceholders(out, stripSections(sql, dc.d), dc.d)
 			if added != len(args) {
 				t.Fatalf("[%s] added=%d, len(args)=%d\nOUT:\n%s", dc.name, added, len(args), out)
 			}
@@ -2616,8 +3270,16 @@ func TestNonIdentifierAfterColon_NoMatch_AllDialects(t *testing.T) {
 		if strings.Contains(out, "@p") || strings.Contains(out, "$") || strings.Contains(out, "?") == false {
 			// soft check; the strong one is placeholder count == len(args)
 		}
-		if got, want := countPlaceholders(out, dc.d), len(args); got != want {
-			t.Fatalf("[%s] placeholder=%d, len(args)=%d\nOUT:\n%s", dcase{d: dc.d}.name, got, want, out)
+		want := len(args)
+		if dc.d == Oracle {
+			// The untouched ":9" reads as an Oracle placeholder too.
+			want++
+			if out != "SELECT :9, :-x, : , :., :1, :2 FROM t WHERE v=:3" {
+				t.Fatalf("[%s] unexpected SQL: %s", dc.name, out)
+			}
+		}
+		if got := countPlaceholders(out, dc.d); got != want {
+			t.Fatalf("[%s] placeholder=%d, want %d, len(args)=%d\nOUT:\n%s", dc.name, got, want, len(args), out)
 		}
 		// invalid tokens must remain textual (e.g. ":9")
 		if !strings.Contains(out, ":9") || !strings.Contains(out, ":-x") {
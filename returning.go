@@ -0,0 +1,387 @@
+package sqlr
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ExecQueryer abstracts *sql.DB / *sql.Tx / *sql.Conn for helpers that either
+// execute or query depending on the dialect.
+type ExecQueryer interface {
+	Execer
+	Queryer
+}
+
+// ExecReturning builds and executes an INSERT, UPDATE, DELETE or MERGE
+// statement and scans the values of cols (all columns if none are given) of
+// the affected rows into dest, adding the clause of the dialect:
+//
+//	Postgres, SQLite: ... RETURNING cols
+//	SQL Server:       INSERT INTO t (...) OUTPUT INSERTED.cols VALUES ...
+//	                  (after SET for UPDATE, at the end for MERGE, DELETED.cols for DELETE)
+//
+// dest is a pointer to a struct or value, scanned like ScanOne, or a pointer
+// to a slice. A non-empty slice of structs, such as the one passed to Insert,
+// is filled in place, one returned row per element in order, and the number
+// of rows must match; other slices are filled like ScanAll. In-place filling
+// relies on Postgres returning the rows of a multi-row INSERT ... VALUES in
+// the order given, which it does without documenting it as guaranteed. SQLite
+// RETURNING and SQL Server OUTPUT return rows in no particular order, so there
+// a slice of more than one element is refused: scan into an empty slice with
+// the key columns instead (Syntax.OrderedReturning).
+//
+// MySQL has no such clause: the statement must be an INSERT, and the id
+// reported by LastInsertId is set on the first pk field of dest, or on the
+// single column named in cols. The elements of a slice receive consecutive
+// ids starting from it, as MySQL assigns them for a multi-row INSERT ...
+// VALUES with auto_increment_increment = 1. The number of rows affected must
+// match the number of elements. Oracle is not supported; custom dialects
+// pick one of these forms with Syntax.Returning.
+func (b *Builder) ExecReturning(ctx context.Context, db ExecQueryer, dest any, cols ...string) error {
+	s := b.s // read before Build() hands the builder back to the pool
+	if b.tx != nil {
+		db = b.tx
+	}
+	q, args, err := b.Build()
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("sqlr: dest must be a non-nil pointer")
+	}
+	if s.syntax.Returning == ReturningInsertID {
+		return s.execInsertID(ctx, db, q, args, rv.Elem(), cols)
+	}
+	if q, err = s.returningSQL(q, cols); err != nil {
+		return err
+	}
+	info := QueryInfo{Op: OpReturning, SQL: q, Args: args, Dialect: s.dialect}
+	ctx, start := s.hookBefore(ctx, &info)
+	info.Rows, err = s.queryReturning(ctx, db, q, args, rv)
+	s.hookAfter(ctx, &info, start, err)
+	return err
+}
+
+// returningSQL adds the clause returning cols to the statement q.
+func (s *SQLR) returningSQL(q string, cols []string) (string, error) {
+	idents := make([]string, len(cols))
+	for i, c := range cols {
+		id, err := s.crudIdent(c)
+		if err != nil {
+			return "", err
+		}
+		idents[i] = id
+	}
+	list := func(prefix string) string {
+		if len(cols) == 0 {
+			return prefix + "*"
+		}
+		return prefix + strings.Join(idents, ", "+prefix)
+	}
+	// Clauses added at the end go before a terminating semicolon or
+	// trailing comments.
+	end, err := s.codeEnd(q)
+	if err != nil {
+		return "", err
+	}
+
+	switch s.syntax.Returning {
+	case ReturningClause:
+		return q[:end] + " RETURNING " + list("") + q[end:], nil
+	case ReturningOutput:
+		// OUTPUT sits before the source rows of an INSERT, after the SET
+		// list of an UPDATE, after the target of a DELETE and at the end of
+		// a MERGE.
+		verb, pos, prev := "", -1, ""
+		err := s.stmtWords(q, func(w string, at int) bool {
+			w = strings.ToUpper(w)
+			switch verb {
+			case "":
+				if slices.Contains([]string{"INSERT", "UPDATE", "DELETE", "MERGE"}, w) {
+					verb = w
+				}
+			case "INSERT":
+				if slices.Contains([]string{"VALUES", "SELECT", "DEFAULT", "EXEC", "EXECUTE"}, w) {
+					pos = at
+				}
+			case "UPDATE":
+				if w == "FROM" || w == "WHERE" || w == "OPTION" {
+					pos = at
+				}
+			case "DELETE":
+				if w == "FROM" && prev != "DELETE" || w == "WHERE" || w == "OPTION" {
+					pos = at
+				}
+			}
+			prev = w
+			return pos < 0 && verb != "MERGE"
+		})
+		if err != nil {
+			return "", err
+		}
+		prefix := "INSERTED."
+		switch verb {
+		case "":
+			return "", fmt.Errorf("sqlr: ExecReturning expects an INSERT, UPDATE, DELETE or MERGE statement")
+		case "INSERT":
+			if pos < 0 {
+				return "", fmt.Errorf("sqlr: ExecReturning cannot find the source rows of the INSERT")
+			}
+		case "DELETE":
+			prefix = "DELETED."
+		}
+		if pos < 0 {
+			return q[:end] + " OUTPUT " + list(prefix) + q[end:], nil
+		}
+		return strings.TrimRight(q[:pos], " \t\r\n") + " OUTPUT " + list(prefix) + " " + q[pos:], nil
+	default:
+		return "", fmt.Errorf("sqlr: ExecReturning is not supported for dialect %s", s.dialect)
+	}
+}
+
+// queryReturning runs q and scans the returned rows into dest (see
+// ExecReturning). It returns the number of rows scanned.
+func (s *SQLR) queryReturning(ctx context.Context, db Queryer, q string, args []any, dest reflect.Value) (int64, error) {
+	fm := s.mapper
+	sv := dest.Elem()
+	if sv.Kind() != reflect.Slice {
+		if err := queryOne(ctx, db, q, args, dest.Interface(), fm); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+	t, ok := inPlaceStruct(sv)
+	if !ok {
+		if err := queryAll(ctx, db, q, args, dest.Interface(), fm); err != nil {
+			return 0, err
+		}
+		return int64(sv.Len()), nil
+	}
+	if !s.syntax.OrderedReturning && sv.Len() > 1 {
+		return 0, fmt.Errorf("sqlr: ExecReturning cannot fill %d elements in place on %s, which returns rows in no guaranteed order; scan into an empty slice instead", sv.Len(), s.dialect)
+	}
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	plan, err := fm.getScanPlan(cols, t)
+	if err != nil {
+		return 0, err
+	}
+	st := plan.newState()
+	n := 0
+	for rows.Next() {
+		if n == sv.Len() {
+			return int64(n), fmt.Errorf("sqlr: more rows returned than the %d elements of dest", sv.Len())
+		}
+		if err := st.scanRow(rows, plan, structElem(sv, n)); err != nil {
+			return int64(n), err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return int64(n), err
+	}
+	if n != sv.Len() {
+		return int64(n), fmt.Errorf("sqlr: %d rows returned for the %d elements of dest", n, sv.Len())
+	}
+	return int64(n), nil
+}
+
+// execInsertID runs the INSERT q and sets the insert id on dest, a struct or
+// a slice of structs (see ExecReturning).
+func (s *SQLR) execInsertID(ctx context.Context, db Execer, q string, args []any, dest reflect.Value, cols []string) error {
+	var verb string
+	if err := s.stmtWords(q, func(w string, _ int) bool {
+		verb = strings.ToUpper(w)
+		return false
+	}); err != nil {
+		return err
+	}
+	if verb != "INSERT" && verb != "REPLACE" {
+		return fmt.Errorf("sqlr: ExecReturning on %s only supports INSERT statements", s.dialect)
+	}
+	n, t := 1, dest.Type()
+	if dest.Kind() == reflect.Slice {
+		n, t = dest.Len(), canonicalStructType(t.Elem())
+	}
+	if t.Kind() != reflect.Struct || n == 0 {
+		return fmt.Errorf("sqlr: ExecReturning on %s needs a struct or a non-empty slice of structs, got %s", s.dialect, dest.Type())
+	}
+	f, err := s.insertIDField(t, cols)
+	if err != nil {
+		return err
+	}
+
+	info := QueryInfo{Op: OpReturning, SQL: q, Args: args, Dialect: s.dialect, Rows: -1}
+	ctx, start := s.hookBefore(ctx, &info)
+	res, err := db.ExecContext(ctx, q, args...)
+	if err == nil {
+		err = setInsertIDs(res, dest, n, f.index)
+	}
+	if err == nil {
+		info.Rows = int64(n)
+	}
+	s.hookAfter(ctx, &info, start, err)
+	return err
+}
+
+// setInsertIDs sets the id reported by res on the field at path of dest, or
+// consecutive ids on the n elements of the slice dest.
+func setInsertIDs(res sql.Result, dest reflect.Value, n int, path []int) error {
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected != int64(n) {
+		return fmt.Errorf("sqlr: %d rows affected for the %d elements of dest; insert ids not set", affected, n)
+	}
+	for i := range n {
+		el := dest
+		if dest.Kind() == reflect.Slice {
+			el = structElem(dest, i)
+		}
+		fv := fieldByIndexAlloc(el, path)
+		if fv.Kind() == reflect.Pointer {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fv = fv.Elem()
+		}
+		if fv.CanInt() {
+			fv.SetInt(id + int64(i))
+		} else {
+			fv.SetUint(uint64(id) + uint64(i))
+		}
+	}
+	return nil
+}
+
+// insertIDField returns the integer field receiving the insert id: the single
+// column in cols, or the first pk field of t.
+func (s *SQLR) insertIDField(t reflect.Type, cols []string) (fieldInfo, error) {
+	m := s.mapper.fieldIndexMap(t)
+	var f fieldInfo
+	switch len(cols) {
+	case 0:
+		for _, fi := range m {
+			if fi.pk && (f.index == nil || slices.Compare(fi.index, f.index) < 0) {
+				f = fi
+			}
+		}
+		if f.index == nil {
+			return f, fmt.Errorf("sqlr: %s has no `db:\",pk\"` field to receive the insert id", t)
+		}
+	case 1:
+		fi, ok := m[s.mapper.key(cols[0])]
+		if !ok || fi.ambiguous {
+			return f, fmt.Errorf("%w: %q in %s", ErrColumnNotFound, cols[0], t)
+		}
+		f = fi
+	default:
+		return f, fmt.Errorf("sqlr: ExecReturning on %s returns only the insert id, got %d columns", s.dialect, len(cols))
+	}
+	ft := t.FieldByIndex(f.index).Type
+	if ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+	switch ft.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return f, nil
+	}
+	return f, fmt.Errorf("sqlr: field %q of %s cannot hold the insert id (%s)", f.col, t, ft)
+}
+
+// inPlaceStruct reports whether the slice sv is filled in place by
+// ExecReturning and returns its struct type.
+func inPlaceStruct(sv reflect.Value) (reflect.Type, bool) {
+	t := canonicalStructType(sv.Type().Elem())
+	ok := sv.Len() > 0 && t.Kind() == reflect.Struct && !hasMany(t) &&
+		!reflect.PointerTo(t).Implements(scannerIface)
+	return t, ok
+}
+
+// structElem returns the struct of the i-th element of sv, allocating it when
+// the element is a nil pointer.
+func structElem(sv reflect.Value, i int) reflect.Value {
+	el := sv.Index(i)
+	if el.Kind() == reflect.Pointer {
+		if el.IsNil() {
+			el.Set(reflect.New(el.Type().Elem()))
+		}
+		el = el.Elem()
+	}
+	return el
+}
+
+// --------------------------------
+// Statement keywords
+// --------------------------------
+
+// stmtWords calls fn with each word of q found outside quotes, comments and
+// parentheses, and its offset, until fn returns false. Words directly after
+// '.' or '@' (qualified names, variables) are not reported.
+func (s *SQLR) stmtWords(q string, fn func(word string, at int) bool) error {
+	lx := lexer{q: q, syn: s.syntax, config: s.config, split: true}
+	depth := 0
+	for {
+		tok, ok, err := lx.next()
+		if err != nil || !ok {
+			return err
+		}
+		if tok.kind != tkText {
+			continue
+		}
+		for i, text := 0, tok.text; i < len(text); {
+			switch c := text[i]; {
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+			case isAlphaUnderscore(c):
+				j := i + 1
+				for j < len(text) && isAlphaNumUnderscore(text[j]) {
+					j++
+				}
+				at := tok.pos + i
+				if depth == 0 && (at == 0 || q[at-1] != '.' && q[at-1] != '@') && !fn(text[i:j], at) {
+					return nil
+				}
+				i = j
+				continue
+			}
+			i++
+		}
+	}
+}
+
+// codeEnd returns the offset just past the last character of q that is not
+// whitespace, a semicolon or part of a comment.
+func (s *SQLR) codeEnd(q string) (int, error) {
+	lx := lexer{q: q, syn: s.syntax, config: s.config, split: true}
+	end := 0
+	for {
+		tok, ok, err := lx.next()
+		if err != nil || !ok {
+			return end, err
+		}
+		switch tok.kind {
+		case tkText:
+			if code := strings.TrimRight(tok.text, " \t\r\n;"); code != "" {
+				end = tok.pos + len(code)
+			}
+		case tkQuoted:
+			end = tok.pos + len(tok.text)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package sqlr
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestExecReturning_Clause verifies where the RETURNING/OUTPUT clause is added
+// for each statement kind, skipping quotes, comments and subqueries.
+func TestExecReturning_Clause(t *testing.T) {
+	tests := []struct {
+		d    Dialect
+		sql  string
+		cols []string
+		want string
+	}{
+		{Postgres, "INSERT INTO t (a) VALUES ($1);", []string{"id", "created at"},
+			`INSERT INTO t (a) VALUES ($1) RETURNING "id", "created at";`},
+		{SQLite, "UPDATE t SET a = ? WHERE id = ? -- note", nil,
+			"UPDATE t SET a = ? WHERE id = ? RETURNING * -- note"},
+		{Postgres, "UPDATE t SET a = 'x;' /* done */;\n", nil,
+			"UPDATE t SET a = 'x;' RETURNING * /* done */;\n"},
+		{SQLServer, "DELETE /* FROM */ FROM t WHERE note = 'where'", nil,
+			"DELETE /* FROM */ FROM t OUTPUT DELETED.* WHERE note = 'where'"},
+		{SQLServer, "INSERT INTO t (a, [values]) VALUES (@p1, 'select')", []string{"id"},
+			"INSERT INTO t (a, [values]) OUTPUT INSERTED.[id] VALUES (@p1, 'select')"},
+		{SQLServer, "WITH s AS (SELECT 1 AS x) INSERT INTO t (a)\nSELECT x FROM s", []string{"id"},
+			"WITH s AS (SELECT 1 AS x) INSERT INTO t (a) OUTPUT INSERTED.[id] SELECT x FROM s"},
+		{SQLServer, "UPDATE t SET a = (SELECT MAX(b) FROM u) WHERE t.id = @p1", []string{"a"},
+			"UPDATE t SET a = (SELECT MAX(b) FROM u) OUTPUT INSERTED.[a] WHERE t.id = @p1"},
+		{SQLServer, "UPDATE t SET a = 1", nil, "UPDATE t SET a = 1 OUTPUT INSERTED.*"},
+		{SQLServer, "DELETE FROM t WHERE id = @p1", []string{"id"},
+			"DELETE FROM t OUTPUT DELETED.[id] WHERE id = @p1"},
+		{SQLServer, "MERGE INTO t AS target USING s ON target.id = s.id WHEN NOT MATCHED THEN INSERT (id) VALUES (s.id);",
+			[]string{"id"},
+			"MERGE INTO t AS target USING s ON target.id = s.id WHEN NOT MATCHED THEN INSERT (id) VALUES (s.id) OUTPUT INSERTED.[id];"},
+	}
+	for _, tc := range tests {
+		got, err := New(tc.d).returningSQL(tc.sql, tc.cols)
+		assertNoError(t, err)
+		if got != tc.want {
+			t.Fatalf("[%s] %q:\n got=%s\nwant=%s", tc.d, tc.sql, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		d    Dialect
+		sql  string
+		want string
+	}{
+		{SQLServer, "SELECT 1", "expects an INSERT"},
+		{SQLServer, "INSERT INTO t DEFAULT_VALUES", "source rows"},
+		{Oracle, "INSERT INTO t (a) VALUES (:1)", "not supported for dialect oracle"},
+		{testDialect, "INSERT INTO t (a) VALUES (?)", "not supported"},
+	} {
+		if _, err := New(tc.d).returningSQL(tc.sql, nil); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("[%s] %q: want %q error, got %v", tc.d, tc.sql, tc.want, err)
+		}
+	}
+	if _, err := New(Postgres).returningSQL("DELETE FROM t", []string{"id", "t."}); !errors.Is(err, ErrIdentInvalid) {
+		t.Fatalf("empty column part: want ErrIdentInvalid, got %v", err)
+	}
+}
+
+// TestExecReturning_ScansInPlace verifies that returned rows fill the bound
+// struct, or the elements of the bound slice in order, keeping other fields,
+// and that only Postgres fills several elements in place.
+func TestExecReturning_ScansInPlace(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	s := New(Postgres)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	users := []*crudUser{{Name: "Ann"}, {Name: "Bob"}}
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("id", "name", "email", "audit_by") VALUES ($1, $2, $3, $4), ($5, $6, $7, $8) RETURNING "id", "created_at"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, now).AddRow(2, now))
+	assertNoError(t, s.Insert("users", users).ExecReturning(ctx, db, &users, "id", "created_at"))
+	if users[0].ID != 1 || users[0].Name != "Ann" || users[1].ID != 2 || users[1].Name != "Bob" || !users[1].CreatedAt.Equal(now) {
+		t.Fatalf("unexpected rows: %+v %+v", *users[0], *users[1])
+	}
+
+	u := crudUser{ID: 1, Name: "Ann"}
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "users" SET "name" = $1, "audit_by" = $2 WHERE "id" = $3 RETURNING "created_at"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	assertNoError(t, s.Update("users", &u).ExecReturning(ctx, db, &u, "created_at"))
+	if u.Name != "Ann" || !u.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected row: %+v", u)
+	}
+
+	// Other slices are filled like ScanAll.
+	var ids []int64
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM users WHERE name = $1 RETURNING "id"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5))
+	assertNoError(t, s.Write("DELETE FROM users WHERE name = :n").Bind("n", "x").ExecReturning(ctx, db, &ids, "id"))
+	assertArgsEqual(t, []any{ids[0], ids[1]}, []any{int64(4), int64(5)})
+
+	mock.ExpectQuery("INSERT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	err := s.Insert("users", users).ExecReturning(ctx, db, &users, "id")
+	if err == nil || !strings.Contains(err.Error(), "1 rows returned for the 2 elements") {
+		t.Fatalf("want row count error, got %v", err)
+	}
+
+	// A registered Postgres-compatible dialect inherits RETURNING and the
+	// ordered in-place fill.
+	mock.ExpectQuery(regexp.QuoteMeta(`VALUES ($1, $2, $3, $4), ($5, $6, $7, $8) RETURNING "id"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6))
+	assertNoError(t, New(pgCompatDialect).Insert("users", users).ExecReturning(ctx, db, &users, "id"))
+	if users[0].ID != 5 || users[1].ID != 6 {
+		t.Fatalf("unexpected ids: %+v %+v", *users[0], *users[1])
+	}
+
+	// Without an order guarantee, several elements are not filled in place,
+	// but a single one is, and an empty slice is scanned into.
+	for _, d := range []Dialect{SQLite, SQLServer} {
+		err := New(d).Insert("users", users).ExecReturning(ctx, db, &users, "id")
+		if err == nil || !strings.Contains(err.Error(), "cannot fill 2 elements in place") {
+			t.Fatalf("[%s] want order error, got %v", d, err)
+		}
+	}
+	one := []crudUser{{Name: "Cid"}}
+	mock.ExpectQuery(regexp.QuoteMeta("OUTPUT INSERTED.[id] VALUES")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	assertNoError(t, New(SQLServer).Insert("users", one).ExecReturning(ctx, db, &one, "id"))
+	var created []crudUser
+	mock.ExpectQuery(regexp.QuoteMeta("OUTPUT INSERTED.[id], INSERTED.[name] VALUES")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(9, "Bob").AddRow(8, "Ann"))
+	assertNoError(t, New(SQLServer).Insert("users", users).ExecReturning(ctx, db, &created, "id", "name"))
+	if one[0].ID != 7 || len(created) != 2 || created[0].Name != "Bob" || created[1].ID != 8 {
+		t.Fatalf("unexpected rows: %+v %+v", one, created)
+	}
+	assertNoError(t, mock.ExpectationsWereMet())
+}
+
+// TestExecReturning_MySQLInsertID verifies the LastInsertId fallback for one
+// and many rows, and its checks.
+func TestExecReturning_MySQLInsertID(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	var calls []string
+	h := &recHook{name: "h", calls: &calls}
+	s := New(MySQL, Config{Hooks: []Hook{h}})
+
+	users := []crudUser{{Name: "Ann"}, {Name: "Bob"}, {Name: "Cid"}}
+	mock.ExpectExec("INSERT INTO `users`").WillReturnResult(sqlmock.NewResult(10, 3))
+	assertNoError(t, s.Insert("users", users).ExecReturning(ctx, db, &users))
+	if users[0].ID != 10 || users[1].ID != 11 || users[2].ID != 12 || users[2].Name != "Cid" {
+		t.Fatalf("unexpected ids: %+v", users)
+	}
+	if info := h.after[0]; info.Op != OpReturning || info.Rows != 3 || info.Op.String() != "returning" {
+		t.Fatalf("hook info: %+v", info)
+	}
+
+	var row struct {
+		Seq  *uint32 `db:"seq"`
+		Name string  `db:"name"`
+	}
+	row.Name = "x"
+	mock.ExpectExec("INSERT INTO `logs`").WillReturnResult(sqlmock.NewResult(42, 1))
+	assertNoError(t, s.Insert("logs", &row).ExecReturning(ctx, db, &row, "seq"))
+	if row.Seq == nil || *row.Seq != 42 {
+		t.Fatalf("unexpected seq: %v", row.Seq)
+	}
+
+	mock.ExpectExec("INSERT INTO `users`").WillReturnResult(sqlmock.NewResult(10, 2))
+	if err := s.Insert("users", users).ExecReturning(ctx, db, &users); err == nil || !strings.Contains(err.Error(), "2 rows affected for the 3 elements") {
+		t.Fatalf("want affected rows error, got %v", err)
+	}
+	assertNoError(t, mock.ExpectationsWereMet())
+
+	for _, tc := range []struct {
+		b    *Builder
+		dest any
+		cols []string
+		want string
+	}{
+		{s.Update("users", users[0]), &users[0], nil, "only supports INSERT"},
+		{s.Insert("logs", &row), &row, nil, "no `db:\",pk\"` field"},
+		{s.Insert("logs", &row), &row, []string{"name"}, "cannot hold the insert id"},
+		{s.Insert("logs", &row), &row, []string{"seq", "name"}, "only the insert id"},
+		{s.Insert("users", users), &[]crudUser{}, nil, "non-empty slice"},
+	} {
+		if err := tc.b.ExecReturning(ctx, db, tc.dest, tc.cols...); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("want %q error, got %v", tc.want, err)
+		}
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 )
 
 // Dialect identifies the SQL dialect for placeholder rendering and a few
-// dialect-specific parsing behaviors.
+// dialect-specific parsing behaviors. It is a handle to a DialectSpec: the
+// built-in constants below, or a value returned by RegisterDialect.
 type Dialect int
 
 // SQLR is the main entry point. It holds the selected dialect, configuration,
@@ -18,8 +22,15 @@ type Dialect int
 // A single SQLR instance is safe for concurrent use.
 type SQLR struct {
 	dialect Dialect
+	spec    DialectSpec
+	syntax  Syntax // spec.Syntax(), resolved once
 	config  Config
-	pool    sync.Pool
+	mapper  *fieldMapper
+	// allowIdents is Config.AllowedIdents as a set, nil if unrestricted.
+	allowIdents map[string]struct{}
+	queriesMu   sync.RWMutex
+	queries     map[string]*Stmt // named queries loaded by LoadQueries
+	pool        sync.Pool
 }
 
 // Builder assembles a single SQL statement and bound parameters.
@@ -27,11 +38,13 @@ type SQLR struct {
 // automatically released back to the pool and must not be used again.
 type Builder struct {
 	s        *SQLR
+	stmt     *Stmt // compiled template, if created via Stmt.Bind
 	parts    []string
 	inputs   []any
 	released bool
 	bag      P
 	err      error
+	tx       *sql.Tx // set by Tx: the executing methods run on it
 }
 
 // Config defines limits and behavior tweaks for the parser/binder.
@@ -44,8 +57,64 @@ type Config struct {
 	// MaxNameLen limits the maximum allowed length of a placeholder name,
 	// e.g. ":this_is_a_name". Names longer than this cause ErrParamNameTooLong.
 	MaxNameLen int
+	// ReuseParams makes repeated scalar :name placeholders reuse the ordinal
+	// of their first occurrence ($1 ... $1), binding the value only once.
+	// It applies to dialects with numbered placeholders (Postgres, SQL
+	// Server); others keep one placeholder per occurrence.
+	ReuseParams bool
+	// NamedArgs renders :name as @name and returns sql.NamedArg args
+	// (sql.Named("name", v)), so parameter names survive into server traces.
+	// Slice expansions are suffixed (@ids_1, @ids_2), rows-blocks use
+	// @name_<row>_<col> (<col> is the column position when the column is
+	// not a plain identifier), and repeated names bind once; a generated name
+	// clashing with another placeholder fails with ErrNamedConflict. It
+	// applies to SQL Server and SQLite; other dialects ignore it.
+	NamedArgs bool
+	// NameMapper derives the column name of struct fields without a `db` tag
+	// name, e.g. SnakeCase or strings.ToLower. If nil, the Go field name is used.
+	NameMapper NameMapper
+	// CaseInsensitive matches result columns, :name placeholders and
+	// :name{...} columns to struct fields ignoring case.
+	CaseInsensitive bool
+	// Hooks observe every query run by Exec, Scan, Rows and the other
+	// executing helpers (see Hook). Before hooks run in order, After hooks in
+	// reverse order.
+	Hooks []Hook
+	// StrictColumns makes struct scans fail with ErrColumnUnmapped when a
+	// result column maps to no field, instead of silently discarding it.
+	StrictColumns bool
+	// StrictFields makes struct scans fail with ErrFieldUnfilled when a field
+	// with an explicit `db:"name"` tag receives no result column.
+	StrictFields bool
+	// MapBytesToString converts []byte values to string when scanning into
+	// map[string]any, except for columns whose database type is binary
+	// (BLOB, BYTEA, VARBINARY...) according to rows.ColumnTypes().
+	MapBytesToString bool
+	// EmptySlices selects how an empty slice bound to :name renders
+	// (see EmptyPolicy). The default, EmptyError, fails with ErrSliceEmpty.
+	// EmptyAs overrides it per value.
+	EmptySlices EmptyPolicy
+	// SkipZeroSections also drops /*? ... */ optional sections with a
+	// placeholder bound to nil, a zero value or an empty slice or map.
+	// By default a section is kept as soon as all its placeholders are bound.
+	SkipZeroSections bool
+	// AllowedIdents, if not empty, restricts the identifiers accepted by
+	// :!name placeholders and Ident values to this list. Names are compared
+	// as bound, dots included ("public.users"); others fail with
+	// ErrIdentNotAllowed.
+	AllowedIdents []string
+	// TxRetries is the number of times InTx runs a transaction again after
+	// a retryable error (see TxRetryable). Zero disables retries.
+	TxRetries int
+	// TxRetryable reports whether an InTx error, such as a serialization
+	// failure or a deadlock, is worth retrying. If nil, IsSerializationFailure
+	// is used.
+	TxRetryable func(err error) bool
 }
 
+// NameMapper converts a Go struct field name into a column name.
+type NameMapper func(field string) string
+
 // P is a convenient alias for map[string]any to use with Bind().
 type P = map[string]any
 
@@ -64,6 +133,7 @@ const (
 	MySQL
 	SQLite
 	SQLServer
+	Oracle
 )
 
 const cacheSize = 4096 // Default size for the field-index cache
@@ -79,22 +149,85 @@ var (
 	ErrFieldAmbiguous   = errors.New("sqlr: ambiguous field name")
 	ErrBuilderReleased  = errors.New("sqlr: builder already released; call Write() on *SQLR for a new query")
 	ErrMoreThanOneRow   = errors.New("sqlr: more than one row")
+	ErrColumnUnmapped   = errors.New("sqlr: result column not mapped to any field")
+	ErrFieldUnfilled    = errors.New("sqlr: field not filled by any column")
+	ErrIdentInvalid     = errors.New("sqlr: invalid identifier")
+	ErrIdentNotAllowed  = errors.New("sqlr: identifier not allowed")
+	ErrSectionMalformed = errors.New("sqlr: malformed /*? ... */ section")
+	ErrUnterminated     = errors.New("sqlr: unterminated quote or comment")
+	ErrQueryNotFound    = errors.New("sqlr: query not found")
+	ErrNamedConflict    = errors.New("sqlr: conflicting named argument")
 )
 
+// BuildError reports an error raised while building a statement at a
+// given position of its SQL, such as a missing parameter or a malformed
+// :name{...} block. It wraps the underlying error, so errors.Is still
+// matches the Err* sentinels:
+//
+//	var be *sqlr.BuildError
+//	if errors.As(err, &be) {
+//		log.Printf("%v\n%s", be, be.Snippet)
+//	}
+type BuildError struct {
+	Err     error  // underlying error, wrapping one of the Err* sentinels
+	Offset  int    // byte offset in the SQL
+	Line    int    // 1-based line
+	Column  int    // 1-based column, in characters
+	Snippet string // the offending line and a caret under the column
+}
+
+// Error returns the underlying message followed by the position.
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("%v (line %d, column %d)", e.Err, e.Line, e.Column)
+}
+
+// Unwrap returns the underlying error.
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// newBuildError wraps err with the position pos of sql. Errors that already
+// carry a position are returned unchanged.
+func newBuildError(sql string, pos int, err error) error {
+	var be *BuildError
+	if errors.As(err, &be) {
+		return err
+	}
+	pos = min(max(pos, 0), len(sql))
+	start := strings.LastIndexByte(sql[:pos], '\n') + 1
+	end := strings.IndexByte(sql[pos:], '\n')
+	if end < 0 {
+		end = len(sql)
+	} else {
+		end += pos
+	}
+	line := strings.TrimSuffix(sql[start:end], "\r")
+
+	// The caret line keeps the tabs of the prefix so it stays aligned.
+	var caret strings.Builder
+	col := 1
+	for _, r := range sql[start:pos] {
+		if r == '\t' {
+			caret.WriteByte('\t')
+		} else {
+			caret.WriteByte(' ')
+		}
+		col++
+	}
+	caret.WriteByte('^')
+
+	return &BuildError{
+		Err:     err,
+		Offset:  pos,
+		Line:    strings.Count(sql[:pos], "\n") + 1,
+		Column:  col,
+		Snippet: line + "\n" + caret.String(),
+	}
+}
+
 // String returns the string representation of the dialect.
 func (d Dialect) String() string {
-	switch d {
-	case Postgres:
-		return "postgres"
-	case MySQL:
-		return "mysql"
-	case SQLite:
-		return "sqlite"
-	case SQLServer:
-		return "sqlserver"
-	default:
-		return "unknown"
-	}
+	return d.spec().Name()
 }
 
 // New returns a new SQLR for the given dialect. Optionally provide a Config;
@@ -102,8 +235,17 @@ func (d Dialect) String() string {
 func New(dialect Dialect, cfg ...Config) *SQLR {
 	s := &SQLR{
 		dialect: dialect,
+		spec:    dialect.spec(),
 		config:  defaultConfig(dialect, cfg...),
 	}
+	s.syntax = s.spec.Syntax()
+	s.mapper = newFieldMapper(s.config)
+	if len(s.config.AllowedIdents) > 0 {
+		s.allowIdents = make(map[string]struct{}, len(s.config.AllowedIdents))
+		for _, id := range s.config.AllowedIdents {
+			s.allowIdents[id] = struct{}{}
+		}
+	}
 	s.pool.New = func() any {
 		return &Builder{
 			s:      s,
@@ -119,6 +261,8 @@ func New(dialect Dialect, cfg ...Config) *SQLR {
 func (s *SQLR) Write(sql string) *Builder {
 	b := s.pool.Get().(*Builder)
 	b.s = s
+	b.stmt = nil
+	b.tx = nil
 	b.released = false
 	b.err = nil
 	b.parts = b.parts[:0]
@@ -138,6 +282,7 @@ func (b *Builder) Write(sql string) *Builder {
 	if b.err != nil {
 		return b
 	}
+	b.detachStmt()
 	b.parts = append(b.parts, sql)
 	return b
 }
@@ -151,6 +296,7 @@ func (b *Builder) Writef(format string, args ...any) *Builder {
 	if b.err != nil {
 		return b
 	}
+	b.detachStmt()
 	b.parts = append(b.parts, fmt.Sprintf(format, args...))
 	return b
 }
@@ -159,7 +305,7 @@ func (b *Builder) Writef(format string, args ...any) *Builder {
 //   - nil (ignored)
 //   - struct with `db` tags (flattened through nested structs)
 //   - map[string]any or any reflect.Map
-//   - []struct / []map for :rows{...}
+//   - []struct / []map for :rows{...} (VALUES lists, tuple IN lists)
 //   - slices of primitives for :name expansion
 //   - k/v pairs (even number of args, first is string key)
 //
@@ -208,24 +354,11 @@ func (b *Builder) Build() (string, []any, error) {
 	if b.released {
 		return "", nil, ErrBuilderReleased
 	}
-
-	// Snapshot before defer to avoid races with Release()
-	q := strings.Join(b.parts, "")
-	d := b.s.dialect
-	cfg := b.s.config
-
-	// Local copy of inputs and append bag only if it has something
-	in := b.inputs
-	if len(b.bag) > 0 {
-		in = append(in, b.bag)
-	}
-
 	defer b.Release()
 	if b.err != nil {
 		return "", nil, b.err
 	}
-	out, args, err := parse(d, q, in, cfg)
-	return out, args, err
+	return b.render()
 }
 
 // Preview renders the SQL statement and bound args without releasing the Builder.
@@ -240,19 +373,32 @@ func (b *Builder) Preview() (string, []any, error) {
 	if b.err != nil {
 		return "", nil, b.err
 	}
+	return b.render()
+}
 
-	q := strings.Join(b.parts, "")
-	d := b.s.dialect
-	cfg := b.s.config
-
+// render binds the enqueued inputs and renders the SQL, either from the
+// compiled statement or by parsing the written fragments.
+func (b *Builder) render() (string, []any, error) {
 	// Local copy of inputs; append bag only if it has entries.
 	in := b.inputs
 	if len(b.bag) > 0 {
 		in = append(in, b.bag)
 	}
 
-	out, args, err := parse(d, q, in, cfg)
-	return out, args, err
+	if b.stmt != nil {
+		return b.stmt.render(in)
+	}
+	return parse(b.s, strings.Join(b.parts, ""), in)
+}
+
+// detachStmt turns a compiled-statement builder into a regular one so that
+// further fragments can be appended to the statement source.
+func (b *Builder) detachStmt() {
+	if b.stmt == nil {
+		return
+	}
+	b.parts = append(b.parts, b.stmt.sql)
+	b.stmt = nil
 }
 
 // Release clears the builder and puts it back into the pool.
@@ -273,6 +419,8 @@ func (b *Builder) Release() {
 	}
 	b.inputs = b.inputs[:0]
 
+	b.stmt = nil
+	b.tx = nil
 	b.bag = nil
 	b.err = nil
 	b.s.pool.Put(b)
@@ -284,6 +432,60 @@ func Scalar(v any) any {
 	return scalar{v: v}
 }
 
+// EmptyPolicy selects how an empty slice bound to :name renders.
+type EmptyPolicy uint8
+
+const (
+	// EmptyError fails the build with ErrSliceEmpty.
+	EmptyError EmptyPolicy = iota
+	// EmptyNull renders NULL: x IN (NULL) matches no row, but so does
+	// x NOT IN (NULL).
+	EmptyNull
+	// EmptyFalse renders a subquery returning no rows (SELECT NULL WHERE 1=0,
+	// FROM dual on MySQL, Oracle and dialects with Syntax.FromDual):
+	// x IN (...) is false and x NOT IN (...) is true, as for an empty set.
+	EmptyFalse
+)
+
+// EmptyAs wraps a slice to render it with policy p when it is empty,
+// overriding Config.EmptySlices. Non-empty slices expand as usual.
+func EmptyAs(v any, p EmptyPolicy) any {
+	return emptyAs{v: v, policy: p}
+}
+
+// Ident is a table or column name bound as a value. Wherever it is bound
+// (:name or :!name), it is rendered in the SQL quoted for the dialect
+// ("x" for Postgres, `x` for MySQL, [x] for SQL Server), with embedded quotes
+// escaped, instead of becoming an arg. Dots separate qualified parts:
+// Ident("public.users") renders as "public"."users". A []Ident renders as a
+// comma-separated list.
+type Ident string
+
+// SnakeCase is a NameMapper that converts Go field names to snake_case,
+// keeping initialisms together: "CreatedAt" → "created_at",
+// "UserID" → "user_id", "HTTPServer" → "http_server".
+func SnakeCase(name string) string {
+	var sb strings.Builder
+	sb.Grow(len(name) + 4)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c >= 'A' && c <= 'Z' {
+			if i > 0 && name[i-1] != '_' {
+				prev := name[i-1]
+				prevLowerOrDigit := (prev >= 'a' && prev <= 'z') || (prev >= '0' && prev <= '9')
+				nextLower := i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z'
+				prevUpper := prev >= 'A' && prev <= 'Z'
+				if prevLowerOrDigit || (prevUpper && nextLower) {
+					sb.WriteByte('_')
+				}
+			}
+			c += 'a' - 'A'
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
 // Exec is a convenience that builds and executes the statement with context.Background().
 func (b *Builder) Exec(db Execer) (sql.Result, error) {
 	return b.ExecContext(context.Background(), db)
@@ -291,43 +493,80 @@ func (b *Builder) Exec(db Execer) (sql.Result, error) {
 
 // ScanOne builds and runs the statement, scanning exactly one row into dest.
 // It returns sql.ErrNoRows if no rows are returned. It errors if more than one row.
+// For structs with `db:",many"` fields, all rows are aggregated and must
+// collapse into exactly one parent.
 func (b *Builder) ScanOne(db Queryer, dest any) error {
 	return b.ScanOneContext(context.Background(), db, dest)
 }
 
 // ScanAll builds and runs the statement, scanning all rows into dest slice.
+// For structs with `db:",many"` fields, rows are aggregated into parents.
 func (b *Builder) ScanAll(db Queryer, dest any) error {
 	return b.ScanAllContext(context.Background(), db, dest)
 }
 
 // ExecContext builds and executes the statement with the provided context.
 func (b *Builder) ExecContext(ctx context.Context, db Execer) (sql.Result, error) {
+	s := b.s // read before Build() hands the builder back to the pool
+	if b.tx != nil {
+		db = b.tx // builders from a Tx run on its transaction
+	}
 	q, args, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
-	return db.ExecContext(ctx, q, args...)
+	info := QueryInfo{Op: OpExec, SQL: q, Args: args, Dialect: s.dialect, Rows: -1}
+	ctx, start := s.hookBefore(ctx, &info)
+	res, err := db.ExecContext(ctx, q, args...)
+	if err == nil && len(s.config.Hooks) > 0 {
+		if n, rerr := res.RowsAffected(); rerr == nil {
+			info.Rows = n
+		}
+	}
+	s.hookAfter(ctx, &info, start, err)
+	return res, err
 }
 
 // ScanOneContext is the context-aware variant of ScanOne.
 func (b *Builder) ScanOneContext(ctx context.Context, db Queryer, dest any) error {
+	s := b.s // read before Build() hands the builder back to the pool
+	if b.tx != nil {
+		db = b.tx
+	}
 	q, args, err := b.Build()
 	if err != nil {
 		return err
 	}
+	info := QueryInfo{Op: OpScanOne, SQL: q, Args: args, Dialect: s.dialect}
+	ctx, start := s.hookBefore(ctx, &info)
+	err = queryOne(ctx, db, q, args, dest, s.mapper)
+	if err == nil {
+		info.Rows = 1
+	}
+	s.hookAfter(ctx, &info, start, err)
+	return err
+}
+
+// queryOne runs q and scans exactly one row (or one aggregated parent) into dest.
+func queryOne(ctx context.Context, db Queryer, q string, args []any, dest any, fm *fieldMapper) error {
 	rows, err := db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 
+	if rv := reflect.ValueOf(dest); rv.Kind() == reflect.Pointer && !rv.IsNil() &&
+		rv.Elem().Kind() == reflect.Struct && hasMany(rv.Elem().Type()) {
+		return scanAggregateOne(rows, rv.Elem(), fm)
+	}
+
 	if !rows.Next() {
 		if err := rows.Err(); err != nil {
 			return err
 		}
 		return sql.ErrNoRows
 	}
-	if err := scanOne(rows, dest); err != nil {
+	if err := scanOne(rows, dest, fm); err != nil {
 		return err
 	}
 
@@ -340,16 +579,114 @@ func (b *Builder) ScanOneContext(ctx context.Context, db Queryer, dest any) erro
 
 // ScanAllContext is the context-aware variant of ScanAll.
 func (b *Builder) ScanAllContext(ctx context.Context, db Queryer, dest any) error {
+	s := b.s // read before Build() hands the builder back to the pool
+	if b.tx != nil {
+		db = b.tx
+	}
 	q, args, err := b.Build()
 	if err != nil {
 		return err
 	}
+	info := QueryInfo{Op: OpScanAll, SQL: q, Args: args, Dialect: s.dialect}
+	ctx, start := s.hookBefore(ctx, &info)
+	err = queryAll(ctx, db, q, args, dest, s.mapper)
+	if err == nil && len(s.config.Hooks) > 0 {
+		info.Rows = int64(reflect.ValueOf(dest).Elem().Len())
+	}
+	s.hookAfter(ctx, &info, start, err)
+	return err
+}
+
+// queryAll runs q and scans all rows into the slice pointed to by dest.
+func queryAll(ctx context.Context, db Queryer, q string, args []any, dest any, fm *fieldMapper) error {
 	rows, err := db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
-	return scanAll(rows, dest)
+	return scanAll(rows, dest, fm)
+}
+
+// One builds and runs b, scanning exactly one row into a new T.
+// T follows the same rules as the ScanOne destination (struct, primitive or
+// sql.Scanner). It returns sql.ErrNoRows if no rows are returned and
+// ErrMoreThanOneRow if more than one row is returned.
+func One[T any](ctx context.Context, db Queryer, b *Builder) (T, error) {
+	var v T
+	if err := b.ScanOneContext(ctx, db, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// All builds and runs b, scanning all rows into a new []T.
+// T follows the same rules as the ScanAll element type. The result is nil
+// when the query returns no rows.
+func All[T any](ctx context.Context, db Queryer, b *Builder) ([]T, error) {
+	var out []T
+	if err := b.ScanAllContext(ctx, db, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Rows builds and runs b and returns an iterator over the result rows, each
+// scanned into a T with the same rules as the All/ScanAll element type.
+// Rows are scanned one at a time reusing the cached scan plan and buffers,
+// so memory use does not depend on the number of rows. The *sql.Rows is
+// closed when the loop completes or breaks early.
+//
+// Any error is yielded once, together with the zero T, and ends the
+// iteration. The query runs when the iteration starts; since b is single-use,
+// the returned sequence can be ranged over only once.
+func Rows[T any](ctx context.Context, db Queryer, b *Builder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		s := b.s // read before Build() hands the builder back to the pool
+		if b.tx != nil {
+			db = b.tx
+		}
+		q, args, err := b.Build()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		info := QueryInfo{Op: OpRows, SQL: q, Args: args, Dialect: s.dialect}
+		ctx, start := s.hookBefore(ctx, &info)
+		defer func() { s.hookAfter(ctx, &info, start, err) }()
+
+		rows, err := db.QueryContext(ctx, q, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		rr, err := newRowReader(rows, reflect.TypeFor[T](), s.mapper)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		// A single holder is reused for every row; it is reset first so that
+		// fields not mapped by the columns do not leak between rows.
+		v := new(T)
+		dst := reflect.ValueOf(v).Elem()
+		for rows.Next() {
+			*v = zero
+			if err = rr.read(dst); err != nil {
+				yield(zero, err)
+				return
+			}
+			info.Rows++
+			if !yield(*v, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
 }
 
 // ensureBag makes sure the builder has a P bag for Bind(); creates if needed.
@@ -369,19 +706,15 @@ func defaultConfig(dialect Dialect, config ...Config) Config {
 	}
 
 	if c.MaxParams == 0 {
-		switch dialect {
-		case SQLServer:
-			c.MaxParams = 2100
-		case SQLite:
-			c.MaxParams = 999
-		case Postgres, MySQL:
-			c.MaxParams = 65535
-		}
+		c.MaxParams = dialect.spec().MaxParams()
 	}
 
 	if c.MaxNameLen <= 0 {
 		c.MaxNameLen = 64
 	}
 
+	// Hooks are fixed at New(); later changes to the caller's slice don't apply.
+	c.Hooks = slices.Clone(c.Hooks)
+
 	return c
 }
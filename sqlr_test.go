@@ -26,6 +26,7 @@ func allDialects() []dcase {
 		{"mysql", MySQL},
 		{"sqlite", SQLite},
 		{"sqlserver", SQLServer},
+		{"oracle", Oracle},
 	}
 }
 
@@ -36,6 +37,8 @@ func placeholderRegex(d Dialect) *regexp.Regexp {
 		return regexp.MustCompile(`\$(?:[1-9][0-9]*)`)
 	case SQLServer:
 		return regexp.MustCompile(`@p(?:[1-9][0-9]*)`)
+	case Oracle:
+		return regexp.MustCompile(`:(?:[1-9][0-9]*)`)
 	default: // MySQL, SQLite
 		return regexp.MustCompile(`\?`)
 	}
@@ -0,0 +1,126 @@
+package sqlr
+
+// Stmt is a compiled, reusable SQL template created by SQLR.Compile.
+// The SQL is tokenized once into literal segments, :name slots and
+// :name{...} rows-blocks; each render only resolves the bound values and
+// performs slice/rows expansion.
+// A Stmt is immutable and safe for concurrent use.
+type Stmt struct {
+	s      *SQLR
+	sql    string
+	tokens []token
+	params int // number of placeholder tokens, used to pre-size args
+}
+
+// Compile tokenizes sql once for the SQLR dialect and configuration.
+// Lexical errors (malformed :name{...} blocks, names longer than MaxNameLen)
+// are reported here instead of on every Build().
+func (s *SQLR) Compile(sql string) (*Stmt, error) {
+	tokens, params, err := tokenize(s, sql, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt{s: s, sql: sql, tokens: tokens, params: params}, nil
+}
+
+// tokenize splits sql into tokens and counts the placeholder tokens. In
+// strict mode, quotes and block comments left open are reported as errors.
+func tokenize(s *SQLR, sql string, strict bool) ([]token, int, error) {
+	var tokens []token
+	params := 0
+	lx := lexer{q: sql, syn: s.syntax, config: s.config, strict: strict}
+	for {
+		tok, ok, err := lx.next()
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			return tokens, params, nil
+		}
+		if tok.kind == tkParam || tok.kind == tkRows {
+			params++
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+// ParamRef describes one placeholder occurrence found by Params.
+type ParamRef struct {
+	Name     string
+	Rows     bool     // :name{...} rows-block
+	Cols     []string // column list of a rows-block
+	Ident    bool     // :!name identifier
+	Optional bool     // inside a /*? ... */ section
+	Offset   int      // byte offset of the ':' in the SQL
+}
+
+// Params lists the placeholders referenced by sql, in source order, without
+// binding anything. It uses the same lexer as Build for dialect, so
+// placeholders inside quotes and comments are not reported, and it returns
+// the same lexical errors (malformed :name{...}, names over the default
+// MaxNameLen, malformed sections). Repeated names are reported once per
+// occurrence.
+func Params(dialect Dialect, sql string) ([]ParamRef, error) {
+	lx := lexer{q: sql, syn: dialect.spec().Syntax(), config: defaultConfig(dialect)}
+	var (
+		refs     []ParamRef
+		optional bool
+	)
+	for {
+		tok, ok, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return refs, nil
+		}
+		switch tok.kind {
+		case tkSection:
+			optional = true
+		case tkSectionEnd:
+			optional = false
+		case tkParam, tkRows, tkIdent:
+			refs = append(refs, ParamRef{
+				Name:     tok.name,
+				Rows:     tok.kind == tkRows,
+				Cols:     tok.cols,
+				Ident:    tok.kind == tkIdent,
+				Optional: optional,
+				Offset:   tok.pos,
+			})
+		}
+	}
+}
+
+// SQL returns the source SQL the statement was compiled from.
+func (st *Stmt) SQL() string {
+	return st.sql
+}
+
+// Bind starts a new single-use Builder rendering this statement and enqueues
+// the given parameter source (see Builder.Bind). Call it once per execution.
+// Writing more SQL to the returned Builder falls back to regular parsing.
+func (st *Stmt) Bind(args ...any) *Builder {
+	b := st.s.Write("")
+	b.stmt = st
+	return b.Bind(args...)
+}
+
+// render binds inputs to the compiled tokens and renders the final SQL and args.
+func (st *Stmt) render(inputs []any) (string, []any, error) {
+	return renderTokens(st.s, st.sql, st.tokens, st.params, inputs, nil)
+}
+
+// renderTokens renders the tokens of sql with inputs. When rows is non-nil
+// it replaces the rows bound to the :name{...} block (see ExecChunked).
+func renderTokens(s *SQLR, sql string, tokens []token, params int, inputs []any, rows []rowVal) (string, []any, error) {
+	var e emitter
+	e.init(s, inputs, len(sql), params)
+	e.rows = rows
+	for _, tok := range tokens {
+		if err := e.emit(tok); err != nil {
+			return "", nil, newBuildError(sql, tok.pos, err)
+		}
+	}
+	return e.buf.String(), e.args, nil
+}
@@ -0,0 +1,206 @@
+package sqlr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestStmt_MatchesBuild_AllDialects ensures that rendering a compiled statement produces
+// exactly the same SQL and args as parsing the same text through Write().Build().
+func TestStmt_MatchesBuild_AllDialects(t *testing.T) {
+	type Row struct {
+		A int    `db:"a"`
+		B string `db:"b"`
+	}
+	const q = "INSERT INTO t(a,b) VALUES :rows{a,b}; -- :ignored\n" +
+		"SELECT ':nope', col::int FROM t WHERE id IN (:ids) AND x=:x /* :no */"
+	bind := P{"rows": []Row{{1, "x"}, {2, "y"}}, "ids": []int{7, 8}, "x": "k"}
+
+	for _, dc := range allDialects() {
+		t.Run(dc.name, func(t *testing.T) {
+			s := New(dc.d)
+			want, wantArgs, err := s.Write(q).Bind(bind).Build()
+			assertNoError(t, err)
+
+			st, err := s.Compile(q)
+			assertNoError(t, err)
+			got, gotArgs, err := st.Bind(bind).Build()
+			assertNoError(t, err)
+
+			if got != want {
+				t.Fatalf("compiled SQL differs:\n got=%s\nwant=%s", got, want)
+			}
+			assertArgsEqual(t, gotArgs, wantArgs)
+		})
+	}
+}
+
+// TestStmt_RenderManyTimes_NewInputs verifies that a compiled statement can be rendered
+// repeatedly with different inputs, including different slice lengths.
+func TestStmt_RenderManyTimes_NewInputs(t *testing.T) {
+	st, err := New(Postgres).Compile("SELECT * FROM t WHERE id IN (:ids) AND a=:a")
+	assertNoError(t, err)
+
+	out, args, err := st.Bind("ids", []int{1, 2}, "a", "x").Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE id IN ($1, $2) AND a=$3" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, 2, "x"})
+
+	out, args, err = st.Bind("ids", []int{5}, "a", "y").Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE id IN ($1) AND a=$2" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{5, "y"})
+
+	if st.SQL() != "SELECT * FROM t WHERE id IN (:ids) AND a=:a" {
+		t.Fatalf("SQL() = %q", st.SQL())
+	}
+}
+
+// TestStmt_CompileErrors ensures lexical errors are reported once at compile time.
+func TestStmt_CompileErrors(t *testing.T) {
+	s := New(Postgres)
+	if _, err := s.Compile("INSERT INTO t VALUES :rows{a,"); !errors.Is(err, ErrRowsMalformed) {
+		t.Fatalf("expected ErrRowsMalformed, got: %v", err)
+	}
+	if _, err := s.Compile("SELECT :" + strings.Repeat("a", 65)); !errors.Is(err, ErrParamNameTooLong) {
+		t.Fatalf("expected ErrParamNameTooLong, got: %v", err)
+	}
+}
+
+// TestStmt_BindErrors ensures binding errors are still reported at render time.
+func TestStmt_BindErrors(t *testing.T) {
+	st, err := New(MySQL).Compile("SELECT :a, :b")
+	assertNoError(t, err)
+
+	if _, _, err := st.Bind("a", 1).Build(); !errors.Is(err, ErrParamMissing) {
+		t.Fatalf("expected ErrParamMissing, got: %v", err)
+	}
+	if _, _, err := st.Bind("a", 1, "b").Build(); err == nil || !strings.Contains(err.Error(), "even number") {
+		t.Fatalf("expected even-args error, got: %v", err)
+	}
+}
+
+// TestStmt_WriteAfterBind_FallsBackToParse verifies that appending fragments to a builder
+// obtained from a compiled statement keeps the statement text and parses the whole query.
+func TestStmt_WriteAfterBind_FallsBackToParse(t *testing.T) {
+	st, err := New(Postgres).Compile("SELECT * FROM t WHERE a=:a")
+	assertNoError(t, err)
+
+	out, args, err := st.Bind("a", 1).Write(" AND b=:b").Bind("b", 2).Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE a=$1 AND b=$2" {
+		t.Fatalf("unexpected SQL: %s", out)
+	}
+	assertArgsEqual(t, args, []any{1, 2})
+
+	// The statement itself is untouched and reusable.
+	out, _, err = st.Bind("a", 3).Build()
+	assertNoError(t, err)
+	if out != "SELECT * FROM t WHERE a=$1" {
+		t.Fatalf("statement was modified: %s", out)
+	}
+}
+
+// TestStmt_PooledBuilderReset ensures a builder returned to the pool after rendering a
+// compiled statement does not leak the statement into a later Write().
+func TestStmt_PooledBuilderReset(t *testing.T) {
+	s := New(SQLite)
+	st, err := s.Compile("SELECT :a")
+	assertNoError(t, err)
+	_, _, err = st.Bind("a", 1).Build()
+	assertNoError(t, err)
+
+	out, args, err := s.Write("SELECT 1").Build()
+	assertNoError(t, err)
+	if out != "SELECT 1" || len(args) != 0 {
+		t.Fatalf("unexpected SQL/args: %s %v", out, args)
+	}
+}
+
+// TestStmt_Concurrent_AllDialects renders a shared compiled statement from many goroutines.
+func TestStmt_Concurrent_AllDialects(t *testing.T) {
+	for _, dc := range allDialects() {
+		st, err := New(dc.d).Compile("SELECT :a, :b WHERE id IN (:ids)")
+		assertNoError(t, err)
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					_, args, err := st.Bind("a", g, "b", i, "ids", []int{g, i}).Build()
+					if err != nil {
+						t.Errorf("[%s] build error: %v", dc.name, err)
+						return
+					}
+					if len(args) != 4 || args[0] != g || args[1] != i {
+						t.Errorf("[%s] unexpected args: %v", dc.name, args)
+						return
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+	}
+}
+
+// TestParams_ListsPlaceholders verifies Params reports every placeholder
+// occurrence with its kind, columns, section flag and offset, skipping quoted
+// and commented text per dialect.
+func TestParams_ListsPlaceholders(t *testing.T) {
+	const q = "INSERT INTO :!tbl (a,b) VALUES :rows{a,b} -- :no\n" +
+		"SELECT ':no', x::int FROM t WHERE id IN (:ids) /*? AND n=:n */ AND id=:ids"
+	got, err := Params(Postgres, q)
+	assertNoError(t, err)
+	want := []ParamRef{
+		{Name: "tbl", Ident: true, Offset: strings.Index(q, ":!tbl")},
+		{Name: "rows", Rows: true, Cols: []string{"a", "b"}, Offset: strings.Index(q, ":rows")},
+		{Name: "ids", Offset: strings.Index(q, ":ids")},
+		{Name: "n", Optional: true, Offset: strings.Index(q, ":n ")},
+		{Name: "ids", Offset: strings.LastIndex(q, ":ids")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Params:\n got=%+v\nwant=%+v", got, want)
+	}
+
+	// # starts a comment on MySQL only.
+	if refs, _ := Params(MySQL, "SELECT 1 # :a"); len(refs) != 0 {
+		t.Fatalf("MySQL: unexpected refs %+v", refs)
+	}
+	if refs, _ := Params(Postgres, "SELECT 1 # :a"); len(refs) != 1 || refs[0].Name != "a" {
+		t.Fatalf("Postgres: unexpected refs %+v", refs)
+	}
+
+	if _, err := Params(Postgres, "VALUES :rows{a,"); !errors.Is(err, ErrRowsMalformed) {
+		t.Fatalf("want ErrRowsMalformed, got %v", err)
+	}
+	if refs, err := Params(Postgres, "SELECT 1"); err != nil || refs != nil {
+		t.Fatalf("want no refs, got %+v %v", refs, err)
+	}
+}
+
+// BenchmarkStmt_Render_Medium measures rendering a compiled statement with fresh inputs.
+func BenchmarkStmt_Render_Medium(tb *testing.B) {
+	st, err := New(Postgres).Compile(
+		"SELECT id, name FROM users WHERE tenant=:tenant AND id IN (:ids) AND status=:status " +
+			"AND created_at >= :since ORDER BY id LIMIT :limit")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	ids := []int{1, 2, 3, 4, 5}
+	tb.ReportAllocs()
+	for i := 0; i < tb.N; i++ {
+		_, _, err := st.Bind("tenant", 7, "ids", ids, "status", "active", "since", 0, "limit", 50).Build()
+		if err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package sqlr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Tx is the transaction handed to the InTx callback. Builders created from
+// it (Write, Query, Stmt and the CRUD helpers) run on the transaction: the
+// db argument of their executing methods is ignored and can be nil. Tx also
+// implements Execer and Queryer for builders created elsewhere.
+type Tx struct {
+	s     *SQLR
+	tx    *sql.Tx
+	depth int // savepoint nesting level, 0 for the transaction itself
+}
+
+// InTx runs fn in a transaction started on db with opts. The transaction is
+// committed when fn returns nil and rolled back when it returns an error or
+// panics; the panic is then propagated.
+//
+// When fn or the commit fails with an error for which Config.TxRetryable
+// (IsSerializationFailure by default) reports true, the whole transaction
+// runs again, up to Config.TxRetries times, so fn must be safe to repeat.
+// Use Tx.InTx for nested transactions.
+func (s *SQLR) InTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, fn func(tx Tx) error) error {
+	retryable := s.config.TxRetryable
+	if retryable == nil {
+		retryable = IsSerializationFailure
+	}
+	for attempt := 0; ; attempt++ {
+		err := s.runTx(ctx, db, opts, fn)
+		if err == nil || attempt >= s.config.TxRetries || ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+	}
+}
+
+// runTx runs a single attempt of InTx.
+func (s *SQLR) runTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, fn func(tx Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	done := false
+	defer func() {
+		if !done { // fn panicked
+			_ = tx.Rollback()
+		}
+	}()
+	err = fn(Tx{s: s, tx: tx})
+	done = true
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil && !errors.Is(rerr, sql.ErrTxDone) {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// InTx runs fn in a nested transaction, delimited by a savepoint (SAVE
+// TRANSACTION on SQL Server). The savepoint is released when fn returns nil
+// and rolled back to when it returns an error or panics, so the enclosing
+// transaction can go on. Nested transactions are not retried, and fail for
+// dialects without Syntax.Savepoints.
+func (tx Tx) InTx(ctx context.Context, fn func(tx Tx) error) error {
+	inner := Tx{s: tx.s, tx: tx.tx, depth: tx.depth + 1}
+	set, rollback, release := savepointSQL(tx.s.syntax.Savepoints, "sqlr_sp"+strconv.Itoa(inner.depth))
+	if set == "" {
+		return fmt.Errorf("sqlr: nested transactions are not supported for dialect %s", tx.s.dialect)
+	}
+	if _, err := tx.tx.ExecContext(ctx, set); err != nil {
+		return err
+	}
+	// The rollback must run even if ctx was canceled.
+	undo := func() error {
+		_, err := tx.tx.ExecContext(context.WithoutCancel(ctx), rollback)
+		return err
+	}
+	done := false
+	defer func() {
+		if !done { // fn panicked
+			_ = undo()
+		}
+	}()
+	err := fn(inner)
+	done = true
+	if err != nil {
+		if rerr := undo(); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+	if release != "" {
+		_, err = tx.tx.ExecContext(ctx, release)
+	}
+	return err
+}
+
+// savepointSQL returns the statements setting, rolling back to and releasing
+// the savepoint name in the given style. release is empty where savepoints
+// are not released, and all are empty for SavepointNone.
+func savepointSQL(style SavepointStyle, name string) (set, rollback, release string) {
+	switch style {
+	case SavepointRelease:
+		return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, "RELEASE SAVEPOINT " + name
+	case SavepointNoRelease:
+		return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, ""
+	case SavepointTransaction:
+		return "SAVE TRANSACTION " + name, "ROLLBACK TRANSACTION " + name, ""
+	}
+	return "", "", ""
+}
+
+// IsSerializationFailure is the default Config.TxRetryable. It reports
+// whether err carries the SQLSTATE of a serialization failure (40001) or a
+// deadlock (40P01), as exposed by drivers through a SQLState() string method
+// (pgx, lib/pq). Set Config.TxRetryable for drivers reporting them otherwise.
+func IsSerializationFailure(err error) bool {
+	var e interface{ SQLState() string }
+	if !errors.As(err, &e) {
+		return false
+	}
+	code := e.SQLState()
+	return code == "40001" || code == "40P01"
+}
+
+// --------------------------------
+// Builders
+// --------------------------------
+
+// on binds b to the transaction.
+func (tx Tx) on(b *Builder) *Builder {
+	b.tx = tx.tx
+	return b
+}
+
+// Write starts a new statement that runs on the transaction (see SQLR.Write).
+func (tx Tx) Write(sql string) *Builder { return tx.on(tx.s.Write(sql)) }
+
+// Query starts the named query name on the transaction (see SQLR.Query).
+func (tx Tx) Query(name string) *Builder { return tx.on(tx.s.Query(name)) }
+
+// Stmt binds args to the compiled statement st on the transaction
+// (see Stmt.Bind).
+func (tx Tx) Stmt(st *Stmt, args ...any) *Builder { return tx.on(st.Bind(args...)) }
+
+// Insert is SQLR.Insert on the transaction.
+func (tx Tx) Insert(table string, v any) *Builder { return tx.on(tx.s.Insert(table, v)) }
+
+// Update is SQLR.Update on the transaction.
+func (tx Tx) Update(table string, v any, keys ...string) *Builder {
+	return tx.on(tx.s.Update(table, v, keys...))
+}
+
+// Delete is SQLR.Delete on the transaction.
+func (tx Tx) Delete(table string, v any, keys ...string) *Builder {
+	return tx.on(tx.s.Delete(table, v, keys...))
+}
+
+// Upsert is SQLR.Upsert on the transaction.
+func (tx Tx) Upsert(table string, rows any, conflict, update []string) *Builder {
+	return tx.on(tx.s.Upsert(table, rows, conflict, update))
+}
+
+// ExecContext runs query on the transaction.
+func (tx Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return tx.tx.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs query on the transaction.
+func (tx Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return tx.tx.QueryContext(ctx, query, args...)
+}
@@ -0,0 +1,202 @@
+package sqlr
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// sqlStateErr mimics a driver error exposing its SQLSTATE.
+type sqlStateErr string
+
+func (e sqlStateErr) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateErr) SQLState() string { return string(e) }
+
+// TestInTx_CommitRollbackPanic verifies that builders from the Tx run on the
+// transaction, which is committed on nil and rolled back on error or panic.
+func TestInTx_CommitRollbackPanic(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	s := New(Postgres)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE accounts SET balance = balance - $1 WHERE id = $2")).
+		WithArgs(50, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM ledger")).
+		WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO ledger (id) VALUES ($1)")).
+		WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	var n int
+	err := s.InTx(ctx, db, nil, func(tx Tx) error {
+		if _, err := tx.Write("UPDATE accounts SET balance = balance - :amt WHERE id = :id").
+			Bind("amt", 50, "id", 1).ExecContext(ctx, nil); err != nil {
+			return err
+		}
+		var err error
+		if n, err = One[int](ctx, nil, tx.Write("SELECT COUNT(*) FROM ledger")); err != nil {
+			return err
+		}
+		// Builders created elsewhere take the Tx as db.
+		_, err = s.Write("INSERT INTO ledger (id) VALUES (:id)").Bind("id", 9).ExecContext(ctx, tx)
+		return err
+	})
+	assertNoError(t, err)
+	if n != 3 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+
+	boom := errors.New("boom")
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	if err := s.InTx(ctx, db, nil, func(Tx) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("want boom, got %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	func() {
+		defer func() {
+			if r := recover(); r != "bad" {
+				t.Fatalf("want the panic to propagate, got %v", r)
+			}
+		}()
+		_ = s.InTx(ctx, db, nil, func(Tx) error { panic("bad") })
+	}()
+	assertNoError(t, mock.ExpectationsWereMet())
+}
+
+// TestInTx_Retries verifies that retryable errors from fn or the commit run
+// the transaction again, up to Config.TxRetries times.
+func TestInTx_Retries(t *testing.T) {
+	db, mock := newMockDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	s := New(Postgres, Config{TxRetries: 2})
+
+	// fn fails once, then the commit fails once, then it succeeds.
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(sqlStateErr("40001"))
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	calls := 0
+	err := s.InTx(ctx, db, nil, func(Tx) error {
+		if calls++; calls == 1 {
+			return sqlStateErr("40P01")
+		}
+		return nil
+	})
+	assertNoError(t, err)
+	if calls != 3 {
+		t.Fatalf("want 3 attempts, got %d", calls)
+	}
+
+	// Retries are bounded, and other errors are returned at once.
+	for _, tc := range []struct {
+		err   error
+		calls int
+	}{{sqlStateErr("40001"), 3}, {sqlStateErr("23505"), 1}, {errors.New("plain"), 1}} {
+		calls = 0
+		for range tc.calls {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+		}
+		err := s.InTx(ctx, db, nil, func(Tx) error { calls++; return tc.err })
+		if !errors.Is(err, tc.err) || calls != tc.calls {
+			t.Fatalf("%v: got %v after %d calls, want %d", tc.err, err, calls, tc.calls)
+		}
+	}
+
+	// A custom classifier replaces IsSerializationFailure.
+	s = New(MySQL, Config{TxRetries: 1, TxRetryable: func(err error) bool { return err.Error() == "deadlock" }})
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	calls = 0
+	assertNoError(t, s.InTx(ctx, db, nil, func(Tx) error {
+		if calls++; calls == 1 {
+			return errors.New("deadlock")
+		}
+		return nil
+	}))
+	assertNoError(t, mock.ExpectationsWereMet())
+}
+
+// TestInTx_Savepoints verifies the savepoint statements of nested InTx calls
+// for each dialect, and that a failed nested call leaves the outer
+// transaction usable.
+func TestInTx_Savepoints(t *testing.T) {
+	want := map[Dialect][3]string{
+		Postgres:  {"SAVEPOINT sqlr_sp1", "ROLLBACK TO SAVEPOINT sqlr_sp1", "RELEASE SAVEPOINT sqlr_sp1"},
+		MySQL:     {"SAVEPOINT sqlr_sp1", "ROLLBACK TO SAVEPOINT sqlr_sp1", "RELEASE SAVEPOINT sqlr_sp1"},
+		SQLite:    {"SAVEPOINT sqlr_sp1", "ROLLBACK TO SAVEPOINT sqlr_sp1", "RELEASE SAVEPOINT sqlr_sp1"},
+		SQLServer: {"SAVE TRANSACTION sqlr_sp1", "ROLLBACK TRANSACTION sqlr_sp1", ""},
+		Oracle:    {"SAVEPOINT sqlr_sp1", "ROLLBACK TO SAVEPOINT sqlr_sp1", ""},
+	}
+	ctx := context.Background()
+	for _, dc := range allDialects() {
+		db, mock := newMockDB(t)
+		sp := want[dc.d]
+		s := New(dc.d)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(sp[0])).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE").WillReturnError(errors.New("fk"))
+		mock.ExpectExec(regexp.QuoteMeta(sp[1])).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(sp[0])).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("sqlr_sp2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+		if sp[2] != "" {
+			mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT sqlr_sp2")).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(sp[2])).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectCommit()
+
+		err := s.InTx(ctx, db, nil, func(tx Tx) error {
+			err := tx.InTx(ctx, func(tx Tx) error {
+				_, err := tx.Write("DELETE FROM t").ExecContext(ctx, nil)
+				return err
+			})
+			if err == nil || err.Error() != "fk" {
+				t.Fatalf("[%s] want the nested error, got %v", dc.name, err)
+			}
+			return tx.InTx(ctx, func(tx Tx) error {
+				return tx.InTx(ctx, func(tx Tx) error {
+					_, err := tx.Write("UPDATE t SET a = 1").ExecContext(ctx, nil)
+					return err
+				})
+			})
+		})
+		assertNoError(t, err)
+		assertNoError(t, mock.ExpectationsWereMet())
+		db.Close()
+	}
+
+	// Registered dialects follow Syntax.Savepoints, and fail without it.
+	db, mock := newMockDB(t)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sqlr_sp1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT sqlr_sp1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	assertNoError(t, New(pgCompatDialect).InTx(ctx, db, nil, func(tx Tx) error {
+		return tx.InTx(ctx, func(Tx) error { return nil })
+	}))
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err := New(testDialect).InTx(ctx, db, nil, func(tx Tx) error {
+		return tx.InTx(ctx, func(Tx) error { return nil })
+	})
+	if err == nil || !strings.Contains(err.Error(), "not supported for dialect testdb") {
+		t.Fatalf("want unsupported error, got %v", err)
+	}
+	assertNoError(t, mock.ExpectationsWereMet())
+}